@@ -0,0 +1,61 @@
+package werr
+
+import (
+	"io"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which WriteTo buffers are not reused,
+// so that a single huge trace does not pin memory.
+const maxPooledBuffer = 64 << 10
+
+var _buffers = sync.Pool{ //nolint: gochecknoglobals
+	New: func() any {
+		buf := make([]byte, 0, 512) //nolint: mnd
+
+		return &buf
+	},
+}
+
+// AppendTo appends the string representation of the error, as returned by Error, to dst
+// and returns the extended buffer. With the default formatter the error is rendered
+// without intermediate allocations, unless named arguments need the fmt package.
+func (e Error) AppendTo(dst []byte) []byte {
+	switch f := _formatter.(type) {
+	case defaultChainFormatter:
+		return e.appendDefault(dst, Redacted)
+	case FormatFn:
+		return append(dst, e.Format(f)...)
+	}
+
+	return _formatter.AppendChain(dst, e.chain(Redacted))
+}
+
+// WriteTo writes the string representation of the error, as returned by Error, to w.
+// It implements io.WriterTo and renders into a pooled buffer.
+func (e Error) WriteTo(w io.Writer) (int64, error) {
+	buf := _buffers.Get().(*[]byte) //nolint: forcetypeassert
+	*buf = e.AppendTo((*buf)[:0])
+
+	n, err := w.Write(*buf)
+
+	if cap(*buf) <= maxPooledBuffer {
+		_buffers.Put(buf)
+	}
+
+	return int64(n), err //nolint: wrapcheck
+}
+
+// Append appends the string representation of err to dst and returns the extended buffer.
+// Errors wrapped by werr are rendered with AppendTo, other errors with their Error method.
+// If err is nil, dst is returned unchanged.
+func Append(dst []byte, err error) []byte {
+	switch e := err.(type) { //nolint: errorlint
+	case nil:
+		return dst
+	case Error:
+		return e.AppendTo(dst)
+	}
+
+	return append(dst, err.Error()...)
+}
@@ -0,0 +1,66 @@
+package werr_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+func TestError_AppendTo(t *testing.T) {
+	t.Parallel()
+
+	err := werr.WrapArgs(werr.Wrapf(io.EOF, "read %d", 42), werr.Arg("path", "a b"), werr.Arg("token", werr.Secret("s3cr3t")))
+
+	var e werr.Error
+	require.ErrorAs(t, err, &e)
+
+	out := e.AppendTo([]byte("prefix: "))
+	require.Equal(t, "prefix: "+err.Error(), string(out))
+	require.Contains(t, string(out), `path="a b" token=‹×›`)
+}
+
+func TestError_WriteTo(t *testing.T) {
+	t.Parallel()
+
+	err := werr.Wrapf(io.EOF, "read")
+
+	var e werr.Error
+	require.ErrorAs(t, err, &e)
+
+	var buf bytes.Buffer
+
+	n, writeErr := e.WriteTo(&buf)
+	require.NoError(t, writeErr)
+	require.Equal(t, int64(len(err.Error())), n)
+	require.Equal(t, err.Error(), buf.String())
+}
+
+func TestAppend(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "dst", string(werr.Append([]byte("dst"), nil)))
+	})
+
+	t.Run("with werr error", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrap(werr.Wrapf(io.EOF, "read"))
+		require.Equal(t, err.Error(), string(werr.Append(nil, err)))
+	})
+
+	t.Run("with other error", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("load: %w", werr.Wrap(errors.New("original error")))
+		require.Equal(t, err.Error(), string(werr.Append(nil, err)))
+	})
+}
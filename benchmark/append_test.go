@@ -0,0 +1,84 @@
+package benchmark
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/safeblock-dev/werr"
+)
+
+var bufSink []byte
+
+// createWrapChain returns an error wrapped ten times with messages and named arguments.
+func createWrapChain() error {
+	err := errors.New("benchmark")
+
+	for i := 0; i < 10; i++ {
+		switch i % 3 {
+		case 0:
+			err = werr.Wrap(err)
+		case 1:
+			err = werr.Wrapf(err, "benchmark %d", i)
+		default:
+			err = werr.WrapArgs(err, werr.Arg("id", i), werr.Arg("name", "bench mark"), werr.Arg("token", werr.Secret("s3cr3t")))
+		}
+	}
+
+	return err
+}
+
+// assertAllocs fails the benchmark if fn allocates more than want times per run.
+func assertAllocs(b *testing.B, want float64, fn func()) {
+	b.Helper()
+
+	if allocs := testing.AllocsPerRun(100, fn); allocs > want {
+		b.Fatalf("got %v allocs per run, want at most %v", allocs, want)
+	}
+}
+
+func BenchmarkWrapChainError(b *testing.B) {
+	err := createWrapChain()
+
+	// The rendered string and its buffer.
+	assertAllocs(b, 2, func() { _ = err.Error() })
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		bufSink = append(bufSink[:0], err.Error()...)
+	}
+}
+
+func BenchmarkWrapChainAppend(b *testing.B) {
+	err := createWrapChain()
+	buf := make([]byte, 0, 4096)
+
+	assertAllocs(b, 0, func() { buf = werr.Append(buf[:0], err) })
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		buf = werr.Append(buf[:0], err)
+	}
+
+	bufSink = buf
+}
+
+func BenchmarkWrapChainWriteTo(b *testing.B) {
+	var w io.WriterTo
+	if !errors.As(createWrapChain(), &w) {
+		b.Fatal("werr error does not implement io.WriterTo")
+	}
+
+	assertAllocs(b, 0, func() { _, _ = w.WriteTo(io.Discard) })
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		_, _ = w.WriteTo(io.Discard)
+	}
+}
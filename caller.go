@@ -6,26 +6,30 @@ import (
 
 const defaultCallerSkip = 3
 
-// caller returns the name of the calling function, its file path,
+// caller returns the program counter of the calling function, its name, file path,
 // and line number after skipping `skip` levels in the call stack.
+// The program counter is a return address, as reported by runtime.Callers.
 //
 // Example output:
 //
+//	pc: 0x4a5b3c
 //	funcName: "main.main"
 //	file: "/path/to/your/file/main.go"
 //	line: 42
-func caller(skip int) (string, string, int) {
+func caller(skip int) (uintptr, string, string, int) {
 	// skip current func call.
 	if skip < 1 {
 		skip = 1
 	}
 
-	pc, file, line, ok := runtime.Caller(skip)
-	if !ok {
-		return "", "", 0
+	var pcs [1]uintptr
+
+	// runtime.Callers counts itself as frame 0, unlike runtime.Caller.
+	if runtime.Callers(skip+1, pcs[:]) < 1 {
+		return 0, "", "", 0
 	}
 
-	funcName := runtime.FuncForPC(pc).Name()
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
 
-	return funcName, file, line
+	return pcs[0], frame.Function, frame.File, frame.Line
 }
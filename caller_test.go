@@ -8,11 +8,11 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func c() (string, string, int) { return caller(0) }
-func b() (string, string, int) { return c() }
-func a() (string, string, int) { return b() }
+func c() (uintptr, string, string, int) { return caller(0) }
+func b() (uintptr, string, string, int) { return c() }
+func a() (uintptr, string, string, int) { return b() }
 
-var varFuncName, varFile, varLine = caller(0) //nolint: gochecknoglobals
+var _, varFuncName, varFile, varLine = caller(0) //nolint: gochecknoglobals
 
 func TestCaller(t *testing.T) {
 	t.Parallel()
@@ -20,9 +20,10 @@ func TestCaller(t *testing.T) {
 	t.Run("when called in a function", func(t *testing.T) {
 		t.Parallel()
 
-		funcName, file, line := a()
+		pc, funcName, file, line := a()
 		require.Equal(t, "caller_test.go:11", filepath.Base(file)+":"+strconv.Itoa(line))
 		require.Equal(t, "github.com/safeblock-dev/werr.c", funcName)
+		require.NotZero(t, pc)
 	})
 
 	t.Run("when called from outside", func(t *testing.T) {
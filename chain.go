@@ -0,0 +1,324 @@
+package werr
+
+import (
+	"errors"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ChainFormatter renders a whole error chain into a single buffer.
+// Unlike a FormatFn, it sees every wrap site at once and can align or number them.
+// A FormatFn is adapted to a ChainFormatter by its AppendChain method.
+type ChainFormatter interface {
+	// AppendChain appends the rendering of the chain to dst and returns the extended buffer.
+	AppendChain(dst []byte, c Chain) []byte
+}
+
+// Chain is an error chain passed to a ChainFormatter.
+type Chain struct {
+	Frames    []Frame   // Frames are the wrap sites, outermost first; messages are rendered in the Redaction mode.
+	Cause     error     // Cause is the first error of the chain that is not wrapped by werr, if any.
+	Redaction Redaction // Redaction is the mode the chain is rendered in, see Frame.AppendMessage.
+
+	errs []error // errs are the inner errors of the frames.
+}
+
+// chain returns the consecutive werr layers of the error, starting with this one.
+func (e Error) chain(mode Redaction) Chain {
+	c := Chain{Redaction: mode}
+
+	var err error = e
+	for err != nil {
+		w, ok := err.(Error) //nolint: errorlint
+		if !ok {
+			break
+		}
+
+		f := w.Frame()
+		f.Message = w.text(mode)
+		c.Frames = append(c.Frames, f)
+		c.errs = append(c.errs, w.err)
+		err = w.err
+	}
+
+	c.Cause = err
+
+	return c
+}
+
+// defaultChainFormatter renders chains in the layout of the default formatter in a single pass.
+type defaultChainFormatter struct{}
+
+func (defaultChainFormatter) AppendChain(dst []byte, c Chain) []byte {
+	for _, f := range c.Frames {
+		dst = appendLine(dst, f.File, f.Line, f.FuncName, f.Message, f.Args, c.Redaction)
+	}
+
+	if c.Cause != nil {
+		dst = append(dst, c.Cause.Error()...)
+	}
+
+	return dst
+}
+
+// appendDefault appends the rendering of the chain by the default formatter to dst,
+// walking the layers directly instead of collecting a Chain.
+func (e Error) appendDefault(dst []byte, mode Redaction) []byte {
+	// The layers are walked as values: converting e to an error would allocate.
+	w := e
+
+	for {
+		dst = appendLine(dst, w.file, w.line, w.funcName, w.text(mode), w.Args(), mode)
+
+		inner, ok := w.err.(Error) //nolint: errorlint
+		if !ok {
+			break
+		}
+
+		w = inner
+	}
+
+	if w.err != nil {
+		dst = append(dst, w.err.Error()...)
+	}
+
+	return dst
+}
+
+// sizeHint returns an estimate of the length of the rendering of the chain by the default formatter.
+func (e Error) sizeHint() int {
+	const (
+		perLayer = 16 // perLayer covers the separators and the line number.
+		perField = 16 // perField is a guess of the length of a named argument value.
+		cause    = 32 // cause is a guess of the length of the root cause.
+	)
+
+	n := cause
+
+	for w, ok := e, true; ok; w, ok = w.err.(Error) { //nolint: errorlint
+		n += len(w.funcName) + len(path.Base(w.file)) + len(w.msg) + perLayer
+
+		for _, f := range w.Args() {
+			n += len(f.Key) + perField
+		}
+	}
+
+	return n
+}
+
+// appendLine appends a line of the default formatter:
+// the source location, the function and the message, if any, separated by tabs.
+func appendLine(dst []byte, file string, line int, funcName, text string, args []Field, mode Redaction) []byte {
+	dst = appendLocation(dst, file, line, funcName)
+
+	if text != "" || len(args) > 0 {
+		dst = appendMessage(append(dst, '\t'), text, args, mode)
+	}
+
+	return append(dst, '\n')
+}
+
+// appendLocation appends the source location and the function of a wrap site separated by a tab,
+// as returned by location.
+func appendLocation(dst []byte, file string, line int, funcName string) []byte {
+	pkg, fn := funcName, ""
+	if idx := strings.LastIndex(funcName, "."); idx >= 0 {
+		pkg, fn = funcName[:idx], funcName[idx+1:]
+	}
+
+	dst = append(dst, pkg...)
+	dst = append(dst, '/')
+	dst = append(dst, path.Base(file)...)
+	dst = append(dst, ':')
+	dst = strconv.AppendInt(dst, int64(line), 10) //nolint: mnd
+	dst = append(dst, '\t')
+
+	if fn != "" {
+		dst = append(dst, fn...)
+		dst = append(dst, "()"...)
+	}
+
+	return dst
+}
+
+// appendMessage appends a message followed by the named arguments.
+func appendMessage(dst []byte, text string, args []Field, mode Redaction) []byte {
+	dst = append(dst, text...)
+
+	if len(args) == 0 {
+		return dst
+	}
+
+	if text != "" {
+		dst = append(dst, ' ')
+	}
+
+	return appendFields(dst, args, mode)
+}
+
+// AppendChain renders the chain by calling fn for the outermost wrap site.
+// The inner error passed to fn renders the inner wrap sites with fn when its Error method is called.
+func (fn FormatFn) AppendChain(dst []byte, c Chain) []byte {
+	return append(dst, fn.format(&c, 0)...)
+}
+
+// format renders the wrap sites of the chain from the i-th one.
+func (fn FormatFn) format(c *Chain, i int) string {
+	if i >= len(c.Frames) {
+		if c.Cause == nil {
+			return ""
+		}
+
+		return c.Cause.Error()
+	}
+
+	f := c.Frames[i]
+	inner := &formattedError{fn: fn, chain: c, index: i}
+
+	return fn(f.File, f.Line, f.FuncName, inner, string(f.AppendMessage(nil, c.Redaction)))
+}
+
+// formattedError is passed to a FormatFn in place of the inner error of a wrap site.
+// It renders the inner wrap sites lazily, so formatters rendering the whole chain at
+// the outermost wrap site do not render it for every layer.
+type formattedError struct {
+	fn       FormatFn
+	chain    *Chain
+	index    int // index is the wrap site whose inner error this is.
+	text     string
+	rendered bool
+}
+
+func (e *formattedError) Error() string {
+	if !e.rendered {
+		e.text, e.rendered = e.fn.format(e.chain, e.index+1), true
+	}
+
+	return e.text
+}
+
+func (e *formattedError) Unwrap() error {
+	return e.chain.errs[e.index]
+}
+
+// layer is a wrap site of an error chain, as rendered by the formatters walking the whole chain.
+type layer struct {
+	file     string
+	line     int
+	funcName string
+	msg      string    // msg is the message followed by the named arguments.
+	text     string    // text is the message without the named arguments.
+	args     []Field   // args are the named arguments.
+	mode     Redaction // mode is the redaction mode of msg and text.
+	foreign  bool      // foreign marks a layer wrapping the chain without werr, e.g. with fmt.Errorf; it has no location.
+}
+
+// chainLayers returns the wrap sites of the chain starting at the layer a FormatFn is called for,
+// outermost first, and the first error of the chain that is not a werr layer. Formatters
+// rendering the whole chain at the outermost layer use it instead of the inner error text.
+func chainLayers(file string, line int, funcName string, err error, msg string) ([]layer, error) {
+	if e, ok := err.(*formattedError); ok { //nolint: errorlint
+		c := e.chain
+
+		ls := make([]layer, 0, len(c.Frames)-e.index)
+		for _, f := range c.Frames[e.index:] {
+			ls = append(ls, layer{
+				file:     f.File,
+				line:     f.Line,
+				funcName: f.FuncName,
+				msg:      string(f.AppendMessage(nil, c.Redaction)),
+				text:     f.Message,
+				args:     f.Args,
+				mode:     c.Redaction,
+			})
+		}
+
+		return ls, c.Cause
+	}
+
+	// The formatter is called directly, the message of the outermost layer includes its arguments.
+	ls := []layer{{file: file, line: line, funcName: funcName, msg: msg, text: msg}}
+
+	for err != nil {
+		e, ok := err.(Error) //nolint: errorlint
+		if !ok {
+			return ls, err
+		}
+
+		ls = append(ls, e.layer(Redacted))
+		err = e.err
+	}
+
+	return ls, nil
+}
+
+// layer returns the wrap site of the error rendered in the given redaction mode.
+func (e Error) layer(mode Redaction) layer {
+	return layer{
+		file:     e.file,
+		line:     e.line,
+		funcName: e.funcName,
+		msg:      e.message(mode),
+		text:     e.text(mode),
+		args:     e.Args(),
+		mode:     mode,
+	}
+}
+
+// foreignLayers continues the layers of a chain past the errors wrapping werr layers without
+// being one, e.g. created by fmt.Errorf with %w, so the werr layers beneath are rendered in the
+// style of the formatter instead of the text of the wrapping error. It returns the extended layers
+// and the first error that neither is a werr layer nor wraps one.
+func foreignLayers(ls []layer, err error, mode Redaction) ([]layer, error) {
+	if w, ok := err.(*wrapperError); ok { //nolint: errorlint
+		err = w.err
+	}
+
+	for err != nil {
+		if e, ok := err.(Error); ok { //nolint: errorlint
+			ls = append(ls, e.layer(mode))
+			err = e.err
+
+			continue
+		}
+
+		msg, inner := splitWrapper(err)
+		if inner == nil {
+			return ls, err
+		}
+
+		ls = append(ls, layer{msg: msg, text: msg, mode: mode, foreign: true})
+		err = inner
+	}
+
+	return ls, nil
+}
+
+// splitWrapper splits an error wrapping werr layers without being one into its own message and
+// the wrapped error. The inner error is nil if the error does not wrap werr layers or if its text
+// does not end with the text of the wrapped error, so the message cannot be told apart.
+func splitWrapper(err error) (string, error) {
+	inner := errors.Unwrap(err)
+	if !wrapsLayers(inner) {
+		return "", nil
+	}
+
+	text, innerText := err.Error(), inner.Error()
+	if !strings.HasSuffix(text, innerText) {
+		return "", nil
+	}
+
+	return strings.TrimSuffix(strings.TrimSuffix(text, innerText), ": "), inner
+}
+
+// wrapsLayers reports whether a werr layer is found by unwrapping err.
+func wrapsLayers(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if _, ok := err.(Error); ok { //nolint: errorlint
+			return true
+		}
+	}
+
+	return false
+}
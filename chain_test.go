@@ -0,0 +1,210 @@
+package werr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// numberedFormatter renders a chain with numbered frames.
+type numberedFormatter struct{}
+
+func (numberedFormatter) AppendChain(dst []byte, c Chain) []byte {
+	for i, f := range c.Frames {
+		dst = strconv.AppendInt(dst, int64(i+1), 10)
+		dst = append(dst, ". "...)
+		dst = append(dst, f.FuncName...)
+		dst = append(dst, ' ')
+		dst = f.AppendMessage(dst, c.Redaction)
+		dst = append(dst, '\n')
+	}
+
+	return append(dst, c.Cause.Error()...)
+}
+
+func testChainError() Error {
+	fields := []Field{Arg("id", 7), Arg("token", Secret("s3cr3t"))}
+
+	return Error{
+		file:     "/src/main.go",
+		funcName: "main.main",
+		line:     42,
+		msg:      "loading config",
+		err: Error{
+			file:     "/src/load.go",
+			funcName: "main.load",
+			line:     84,
+			args:     &fields,
+			err:      io.EOF,
+		},
+	}
+}
+
+func TestChainFormatter(t *testing.T) {
+	t.Parallel()
+
+	err := testChainError()
+
+	t.Run("with formatter", func(t *testing.T) {
+		t.Parallel()
+
+		exp := "1. main.main loading config\n2. main.load id=7 token=‹×›\nEOF"
+		require.Equal(t, exp, Formatter{Chain: numberedFormatter{}}.Format(err))
+	})
+
+	t.Run("with unredacted formatter", func(t *testing.T) {
+		t.Parallel()
+
+		exp := "1. main.main loading config\n2. main.load id=7 token=s3cr3t\nEOF"
+		require.Equal(t, exp, Formatter{Redaction: Unredacted, Chain: numberedFormatter{}}.Format(err))
+	})
+
+	t.Run("with default formatter", func(t *testing.T) {
+		t.Parallel()
+
+		exp := "main/main.go:42\tmain()\tloading config\nmain/load.go:84\tload()\tid=7 token=‹×›\nEOF"
+		require.Equal(t, exp, string(defaultChainFormatter{}.AppendChain(nil, err.chain(Redacted))))
+		require.Equal(t, exp, string(FormatFn(defaultFormatter).AppendChain(nil, err.chain(Redacted))))
+		require.Equal(t, exp, string(err.appendDefault(nil, Redacted)))
+	})
+
+	t.Run("with error between layers", func(t *testing.T) {
+		t.Parallel()
+
+		wrapped := Error{file: "/src/main.go", funcName: "main.main", line: 42, err: fmt.Errorf("load: %w", err)}
+
+		c := wrapped.chain(Redacted)
+		require.Len(t, c.Frames, 1)
+		require.Equal(t, "load: main/main.go:42\tmain()\tloading config\nmain/load.go:84\tload()\tid=7 token=‹×›\nEOF",
+			c.Cause.Error())
+	})
+}
+
+func TestFormatFn_AppendChain(t *testing.T) {
+	t.Parallel()
+
+	err := testChainError()
+
+	t.Run("with inner error", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+
+		fn := FormatFn(func(_ string, line int, _ string, err error, msg string) string {
+			calls++
+
+			require.ErrorIs(t, err, io.EOF)
+
+			return strconv.Itoa(line) + " " + msg + ": " + err.Error()
+		})
+
+		require.Equal(t, "42 loading config: 84 id=7 token=‹×›: EOF", string(fn.AppendChain(nil, err.chain(Redacted))))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("when inner error is not rendered", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+
+		fn := FormatFn(func(_ string, _ int, funcName string, _ error, _ string) string {
+			calls++
+
+			return funcName
+		})
+
+		require.Equal(t, "prefix main.main", string(fn.AppendChain([]byte("prefix "), err.chain(Redacted))))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("with whole chain", func(t *testing.T) {
+		t.Parallel()
+
+		fn, tmplErr := TemplateFormatter(`{{range .Frames}}{{.Func}}{{range .Fields}} {{.Key}}={{.Value}}{{end}}; {{end}}{{.Cause}}`)
+		require.NoError(t, tmplErr)
+
+		wrapped := Error{
+			file:     "/src/main.go",
+			funcName: "main.run",
+			line:     7,
+			args:     &[]Field{Arg("path", "/etc/app.yaml")},
+			err:      err,
+		}
+
+		require.Equal(t, "run path=/etc/app.yaml; main; load id=7 token=‹×›; EOF", Formatter{Fn: fn}.Format(wrapped))
+	})
+}
+
+func TestError_Format(t *testing.T) {
+	t.Parallel()
+
+	err := testChainError()
+
+	fn := FormatFn(func(_ string, line int, _ string, err error, msg string) string {
+		return strconv.Itoa(line) + " " + msg + " | " + err.Error()
+	})
+
+	t.Run("with original inner error", func(t *testing.T) {
+		t.Parallel()
+
+		var inner []error
+
+		fn := FormatFn(func(_ string, _ int, _ string, err error, _ string) string {
+			inner = append(inner, err)
+
+			return ""
+		})
+
+		err.Format(fn)
+		require.Equal(t, []error{err.err}, inner)
+
+		_, ok := inner[0].(Error) //nolint: errorlint
+		require.True(t, ok)
+	})
+
+	t.Run("when applied to outermost layer only", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "42 loading config | main/load.go:84\tload()\tid=7 token=‹×›\nEOF", err.Format(fn))
+		require.Equal(t, "42 loading config | 84 id=7 token=‹×› | EOF", Formatter{Fn: fn}.Format(err))
+	})
+}
+
+// nolint: paralleltest
+func TestSetFormatter_InnerError(t *testing.T) {
+	err := testChainError()
+
+	var ok bool
+
+	SetFormatter(func(_ string, _ int, funcName string, err error, _ string) string {
+		if funcName == "main.main" {
+			_, ok = err.(Error) //nolint: errorlint
+		}
+
+		return funcName
+	})
+	defer SetFormatter(nil)
+
+	require.Equal(t, "main.main", err.Error())
+	require.True(t, ok)
+}
+
+// nolint: paralleltest
+func TestSetChainFormatter(t *testing.T) {
+	err := testChainError()
+
+	SetChainFormatter(numberedFormatter{})
+	defer SetChainFormatter(nil)
+
+	require.Equal(t, "1. main.main loading config\n2. main.load id=7 token=‹×›\nEOF", err.Error())
+	require.True(t, strings.HasPrefix(fmt.Sprint(errors.Join(err)), "1. main.main"))
+
+	SetChainFormatter(nil)
+	require.Equal(t, defaultChainFormatter{}, _formatter)
+	require.True(t, strings.HasPrefix(err.Error(), "main/main.go:42\tmain()\tloading config\n"), err.Error())
+}
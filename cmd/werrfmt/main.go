@@ -0,0 +1,117 @@
+// Command werrfmt pretty-prints error traces produced by the werr default formatter.
+//
+// Usage:
+//
+//	werrfmt [flags] [file ...]
+//
+// It reads logs or pasted text containing traces in the tab-separated format of the
+// default formatter, including goroutine stacks recorded by werr.PanicToError:
+//
+//	github.com/acme/app/user.go:21	Load()	load user id=7
+//	github.com/acme/app/db.go:42	query()
+//	connection refused
+//
+// and prints every trace as an aligned table, or as JSON. Other lines are copied as is.
+// Without files, standard input is read.
+//
+// The flags are:
+//
+//	-json        print every trace as a JSON object on its own line, dropping other lines
+//	-color       colorize the output: auto, always or never (default auto, honoring NO_COLOR)
+//	-src dir     print the source around every location from the checkout in dir
+//	-context n   number of source lines printed around a location (default 2)
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	jsonOut = flag.Bool("json", false, "print traces as JSON objects, one per line")
+	color   = flag.String("color", "auto", "colorize the output: auto, always or never")
+	srcDir  = flag.String("src", "", "print the source around locations from the checkout in `dir`")
+	context = flag.Int("context", 2, "number of source lines printed around a location") //nolint: mnd
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: werrfmt [flags] [file ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(paths []string, stdin io.Reader, stdout *os.File) error {
+	colored, err := useColor(*color, stdout)
+	if err != nil {
+		return err
+	}
+
+	r := Renderer{JSON: *jsonOut, Color: colored, Context: *context}
+	if *srcDir != "" {
+		r.Source = NewSource(*srcDir)
+	}
+
+	if len(paths) == 0 {
+		return render(r, stdin, stdout)
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err //nolint: wrapcheck
+		}
+
+		err = render(r, f, stdout)
+		f.Close()
+
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func render(r Renderer, in io.Reader, out io.Writer) error {
+	items, err := Parse(in)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	if err := r.Render(out, items); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// useColor resolves the -color flag. In auto mode the output is colorized
+// if it is a terminal and NO_COLOR is not set.
+func useColor(mode string, out *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+
+		fi, err := out.Stat()
+
+		return err == nil && fi.Mode()&os.ModeCharDevice != 0, nil
+	}
+
+	return false, errors.New("invalid -color value " + mode + ": must be auto, always or never")
+}
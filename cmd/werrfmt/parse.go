@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// Trace is an error trace reconstructed from the text output of the werr default formatter.
+type Trace struct {
+	Frames []Frame `json:"frames"`
+	Cause  string  `json:"cause,omitempty"`
+}
+
+// Frame is a wrap site of a trace, outermost first.
+type Frame struct {
+	Package string      `json:"package"`
+	Func    string      `json:"func"`
+	File    string      `json:"file"`
+	Line    int         `json:"line"`
+	Message string      `json:"message,omitempty"`
+	Panic   *PanicStack `json:"panic,omitempty"`
+}
+
+// PanicStack is the goroutine stack recorded by werr.PanicToError.
+type PanicStack struct {
+	Goroutine string       `json:"goroutine"`
+	Frames    []StackFrame `json:"frames"`
+}
+
+// StackFrame is a call of a goroutine stack.
+type StackFrame struct {
+	Func string `json:"func"`
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// panicMessage is the message of the frames created by werr.PanicToError.
+const panicMessage = "panic recovered"
+
+// frameRe matches a line of the default formatter: "pkg/file.go:21\tfunc()\tmessage".
+var frameRe = regexp.MustCompile(`^\s*(\S+)/([^/\s]+):(\d+)\t(\S*)\(\)(?:\t(.*))?$`) //nolint: gochecknoglobals
+
+// stackLineRe matches the location line of a goroutine stack: "\t/path/file.go:21 +0x1d".
+var stackLineRe = regexp.MustCompile(`^\t(.+):(\d+)(?: \+0x[0-9a-f]+)?$`) //nolint: gochecknoglobals
+
+// Item is a part of the input: either a trace or a line that is not part of any trace.
+type Item struct {
+	Trace *Trace
+	Text  string
+}
+
+// parser splits the input into traces and other lines.
+type parser struct {
+	lines []string
+	pos   int
+}
+
+// Parse reads the input and returns its traces interleaved with the other lines.
+func Parse(r io.Reader) ([]Item, error) {
+	var lines []string
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20) //nolint: mnd
+
+	for sc.Scan() {
+		lines = append(lines, strings.TrimSuffix(sc.Text(), "\r"))
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+
+	p := &parser{lines: lines}
+
+	var items []Item
+
+	for p.pos < len(p.lines) {
+		if t := p.trace(); t != nil {
+			items = append(items, Item{Trace: t})
+
+			continue
+		}
+
+		items = append(items, Item{Text: p.lines[p.pos]})
+		p.pos++
+	}
+
+	return items, nil
+}
+
+// trace parses a trace at the current line, if any.
+func (p *parser) trace() *Trace {
+	var t Trace
+
+	for p.pos < len(p.lines) {
+		f, ok := parseFrame(p.lines[p.pos])
+		if !ok {
+			break
+		}
+
+		p.pos++
+
+		if f.Message == panicMessage {
+			f.Panic = p.panicStack()
+		}
+
+		t.Frames = append(t.Frames, f)
+	}
+
+	if len(t.Frames) == 0 {
+		return nil
+	}
+
+	if p.pos < len(p.lines) && p.lines[p.pos] != "" {
+		t.Cause = p.lines[p.pos]
+		p.pos++
+	}
+
+	return &t
+}
+
+// panicStack parses the goroutine stack following a frame of werr.PanicToError,
+// up to and including the blank line ending it.
+func (p *parser) panicStack() *PanicStack {
+	if p.pos >= len(p.lines) || !strings.HasPrefix(p.lines[p.pos], "goroutine ") {
+		return nil
+	}
+
+	s := &PanicStack{Goroutine: strings.TrimSuffix(p.lines[p.pos], ":")}
+	p.pos++
+
+	for p.pos+1 < len(p.lines) && p.lines[p.pos] != "" {
+		m := stackLineRe.FindStringSubmatch(p.lines[p.pos+1])
+		if m == nil {
+			break
+		}
+
+		line, _ := strconv.Atoi(m[2])
+		s.Frames = append(s.Frames, StackFrame{Func: stackFunc(p.lines[p.pos]), File: m[1], Line: line})
+		p.pos += 2
+	}
+
+	if p.pos < len(p.lines) && p.lines[p.pos] == "" {
+		p.pos++
+	}
+
+	// Drop the frames of the recovery itself: debug.Stack, PanicToError and the deferred call up to panic.
+	for i := len(s.Frames) - 1; i >= 0; i-- {
+		if s.Frames[i].Func == "panic" {
+			s.Frames = s.Frames[i+1:]
+
+			break
+		}
+	}
+
+	return s
+}
+
+// stackFunc returns the function name of a call line of a goroutine stack,
+// without arguments: "main.run(0x1, {0x2})" is "main.run".
+func stackFunc(line string) string {
+	if rest, ok := strings.CutPrefix(line, "created by "); ok {
+		line, _, _ = strings.Cut(rest, " in goroutine ")
+
+		return line
+	}
+
+	if i := strings.LastIndex(line, "("); i > 0 && strings.HasSuffix(line, ")") {
+		return line[:i]
+	}
+
+	return line
+}
+
+// parseFrame parses a line of the default formatter.
+func parseFrame(line string) (Frame, bool) {
+	m := frameRe.FindStringSubmatch(line)
+	if m == nil {
+		return Frame{}, false
+	}
+
+	n, err := strconv.Atoi(m[3])
+	if err != nil {
+		return Frame{}, false
+	}
+
+	// The formatter prints the function name split at its last dot: the part before
+	// prefixes the file and the part after is printed with parentheses.
+	fn := m[1]
+	if m[4] != "" {
+		fn += "." + m[4]
+	}
+
+	return Frame{
+		Package: werr.Frame{FuncName: m[1]}.Package(),
+		Func:    fn,
+		File:    m[2],
+		Line:    n,
+		Message: m[5],
+	}, true
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ANSI escape sequences used by the colorized output.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiDim    = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// Renderer writes parsed items.
+type Renderer struct {
+	JSON    bool    // JSON writes every trace as a JSON object on its own line and drops other lines.
+	Color   bool    // Color highlights locations, functions and causes with ANSI escape sequences.
+	Source  *Source // Source prints the code around every location, if set.
+	Context int     // Context is the number of source lines printed around a location.
+}
+
+// Render writes the items to w.
+func (r Renderer) Render(w io.Writer, items []Item) error {
+	bw := bufio.NewWriter(w)
+
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+
+	for _, item := range items {
+		switch {
+		case item.Trace != nil && r.JSON:
+			if err := enc.Encode(item.Trace); err != nil {
+				return err //nolint: wrapcheck
+			}
+		case item.Trace != nil:
+			r.trace(bw, item.Trace)
+		case !r.JSON:
+			bw.WriteString(item.Text + "\n")
+		}
+	}
+
+	return bw.Flush() //nolint: wrapcheck
+}
+
+// trace writes a trace as a table aligned on the location and function columns.
+func (r Renderer) trace(w *bufio.Writer, t *Trace) {
+	locations := make([]string, len(t.Frames))
+	funcs := make([]string, len(t.Frames))
+
+	var locWidth, funcWidth int
+
+	for i, f := range t.Frames {
+		locations[i] = f.Package + "/" + f.File + ":" + strconv.Itoa(f.Line)
+		funcs[i] = shortFunc(f.Func)
+		locWidth = maxInt(locWidth, len(locations[i]))
+		funcWidth = maxInt(funcWidth, len(funcs[i]))
+	}
+
+	for i, f := range t.Frames {
+		line := r.paint(ansiCyan, locations[i]) + pad(locations[i], locWidth) + "  " +
+			r.paint(ansiBold, funcs[i]) + pad(funcs[i], funcWidth)
+		if f.Message != "" {
+			line += "  " + f.Message
+		}
+
+		w.WriteString(strings.TrimRight(line, " ") + "\n")
+		r.snippet(w, f.Package, f.File, f.Line)
+
+		if f.Panic != nil {
+			r.panicStack(w, f.Panic)
+		}
+	}
+
+	if t.Cause != "" {
+		w.WriteString(r.paint(ansiRed, "cause: "+t.Cause) + "\n")
+	}
+}
+
+// panicStack writes the goroutine stack of a panic frame, indented below it.
+func (r Renderer) panicStack(w *bufio.Writer, s *PanicStack) {
+	w.WriteString("    " + r.paint(ansiDim, s.Goroutine) + "\n")
+
+	var funcWidth int
+	for _, f := range s.Frames {
+		funcWidth = maxInt(funcWidth, len(shortFunc(f.Func)))
+	}
+
+	for _, f := range s.Frames {
+		fn := shortFunc(f.Func)
+		w.WriteString("    " + r.paint(ansiBold, fn) + pad(fn, funcWidth) + "  " +
+			r.paint(ansiCyan, f.File+":"+strconv.Itoa(f.Line)) + "\n")
+	}
+}
+
+// snippet writes the source lines around a location, if the file is found in the checkout.
+func (r Renderer) snippet(w *bufio.Writer, pkg, file string, line int) {
+	if r.Source == nil {
+		return
+	}
+
+	lines, first := r.Source.Lines(pkg, file, line-r.Context, line+r.Context)
+
+	for i, text := range lines {
+		n := first + i
+		text = strings.TrimRight(fmt.Sprintf("%6d  %s", n, text), " ")
+
+		if n == line {
+			w.WriteString(r.paint(ansiYellow, "  > "+text) + "\n")
+		} else {
+			w.WriteString(r.paint(ansiDim, "    "+text) + "\n")
+		}
+	}
+}
+
+// paint wraps the text in the escape sequence if the output is colorized.
+func (r Renderer) paint(code, text string) string {
+	if !r.Color || text == "" {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+// shortFunc strips the package path from a qualified function name:
+// "example.com/pkg.Type.Method" is "pkg.Type.Method".
+func shortFunc(fn string) string {
+	return path.Base(fn)
+}
+
+// pad returns the spaces aligning s to the width.
+func pad(s string, width int) string {
+	return strings.Repeat(" ", width-len(s))
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// Source reads files of a local checkout.
+type Source struct {
+	dir    string
+	module string
+}
+
+// NewSource returns a Source reading files below dir.
+// The module path is read from dir/go.mod, if any, to map import paths to directories.
+func NewSource(dir string) *Source {
+	s := &Source{dir: dir}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+				s.module = strings.Trim(strings.TrimSpace(rest), `"`)
+
+				break
+			}
+		}
+	}
+
+	return s
+}
+
+// Lines returns the lines from..to (1-based, inclusive) of the file of the package
+// and the number of the first returned line. It returns nil if the file is not found.
+func (s *Source) Lines(pkg, file string, from, to int) ([]string, int) {
+	name := s.find(pkg, file)
+	if name == "" {
+		return nil, 0
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, 0
+	}
+	defer f.Close()
+
+	from = maxInt(from, 1)
+
+	var lines []string
+
+	sc := bufio.NewScanner(f)
+	for n := 1; n <= to && sc.Scan(); n++ {
+		if n >= from {
+			lines = append(lines, strings.ReplaceAll(sc.Text(), "\t", "    "))
+		}
+	}
+
+	return lines, from
+}
+
+// find returns the path of the file of the package in the checkout. Packages of the module
+// are mapped by their import path, others are looked up by the longest existing path suffix.
+func (s *Source) find(pkg, file string) string {
+	if s.module != "" && (pkg == s.module || strings.HasPrefix(pkg, s.module+"/")) {
+		name := filepath.Join(s.dir, filepath.FromSlash(strings.TrimPrefix(pkg, s.module)), file)
+		if isFile(name) {
+			return name
+		}
+	}
+
+	for rel := pkg; ; {
+		if name := filepath.Join(s.dir, filepath.FromSlash(rel), file); isFile(name) {
+			return name
+		}
+
+		i := strings.Index(rel, "/")
+		if i < 0 {
+			break
+		}
+
+		rel = rel[i+1:]
+	}
+
+	if name := filepath.Join(s.dir, file); isFile(name) {
+		return name
+	}
+
+	return ""
+}
+
+func isFile(name string) bool {
+	fi, err := os.Stat(name)
+
+	return err == nil && fi.Mode().IsRegular()
+}
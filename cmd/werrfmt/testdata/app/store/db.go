@@ -0,0 +1,7 @@
+package store
+
+import "github.com/safeblock-dev/werr"
+
+func query() error {
+	return werr.Wrap(dial())
+}
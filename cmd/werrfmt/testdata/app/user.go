@@ -0,0 +1,9 @@
+package app
+
+import "github.com/safeblock-dev/werr"
+
+func Load(id int) error {
+	err := find(id)
+
+	return werr.Wrapf(err, "load user id=%d", id)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files") //nolint: gochecknoglobals
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		renderer Renderer
+	}{
+		{name: "table", renderer: Renderer{}},
+		{name: "json", renderer: Renderer{JSON: true}},
+		{name: "color", renderer: Renderer{Color: true}},
+		{name: "source", renderer: Renderer{Source: NewSource(filepath.Join("testdata", "app")), Context: 1}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			in, err := os.Open(filepath.Join("testdata", "trace.input"))
+			require.NoError(t, err)
+			defer in.Close()
+
+			items, err := Parse(in)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			require.NoError(t, tc.renderer.Render(&out, items))
+
+			golden := filepath.Join("testdata", "trace."+tc.name+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, out.Bytes(), 0o600))
+			}
+
+			exp, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			require.Equal(t, string(exp), out.String())
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with werr trace", func(t *testing.T) {
+		t.Parallel()
+
+		items, err := Parse(strings.NewReader("example.com/app/user.go:8\tLoad()\tload user id=7\nexample.com/app/store/db.go:6\tquery()\nconnection refused\n"))
+		require.NoError(t, err)
+		require.Equal(t, []Item{{Trace: &Trace{
+			Frames: []Frame{
+				{Package: "example.com/app", Func: "example.com/app.Load", File: "user.go", Line: 8, Message: "load user id=7"},
+				{Package: "example.com/app/store", Func: "example.com/app/store.query", File: "db.go", Line: 6},
+			},
+			Cause: "connection refused",
+		}}}, items)
+	})
+
+	t.Run("with method", func(t *testing.T) {
+		t.Parallel()
+
+		f, ok := parseFrame("example.com/app/store.(*DB)/db.go:42\tExec()\ttable=users")
+		require.True(t, ok)
+		require.Equal(t, Frame{Package: "example.com/app/store", Func: "example.com/app/store.(*DB).Exec", File: "db.go", Line: 42, Message: "table=users"}, f)
+	})
+
+	t.Run("with other lines", func(t *testing.T) {
+		t.Parallel()
+
+		items, err := Parse(strings.NewReader("starting\nexample.com/app/user.go:8\tLoad()\n\nstopped\n"))
+		require.NoError(t, err)
+		require.Equal(t, []Item{
+			{Text: "starting"},
+			{Trace: &Trace{Frames: []Frame{{Package: "example.com/app", Func: "example.com/app.Load", File: "user.go", Line: 8}}}},
+			{Text: ""},
+			{Text: "stopped"},
+		}, items)
+	})
+
+	t.Run("when not a frame", func(t *testing.T) {
+		t.Parallel()
+
+		for _, line := range []string{"", "user.go:8\tLoad()", "example.com/app/user.go:x\tLoad()", "example.com/app/user.go:8 Load()"} {
+			_, ok := parseFrame(line)
+			require.False(t, ok, line)
+		}
+	})
+}
+
+func TestStackFunc(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "main.run", stackFunc("main.run(0x1, {0x2, 0x3})"))
+	require.Equal(t, "example.com/app.(*Worker).handle", stackFunc("example.com/app.(*Worker).handle(0xc000010000)"))
+	require.Equal(t, "example.com/app.Run", stackFunc("created by example.com/app.Run in goroutine 1"))
+	require.Equal(t, "main.main", stackFunc("main.main()"))
+}
@@ -0,0 +1,162 @@
+// Command werrmigrate rewrites error wrapping with fmt.Errorf and github.com/pkg/errors to werr.
+//
+// Usage:
+//
+//	werrmigrate [flags] [path ...]
+//
+// It rewrites:
+//
+//	fmt.Errorf("doing x: %w", err)       -> werr.Wrapf(err, "doing x")
+//	fmt.Errorf("%w", err)                -> werr.Wrap(err)
+//	errors.Wrap(err, "msg")              -> werr.Wrapf(err, "msg")
+//	errors.Wrapf(err, "msg %d", n)       -> werr.Wrapf(err, "msg %d", n)
+//	errors.WithMessage(err, "msg")       -> werr.Wrapf(err, "msg")
+//	errors.WithStack(err)                -> werr.Wrap(err)
+//	errors.Cause(err)                    -> werr.Cause(err)
+//	errors.New, errors.Is, errors.As     -> the standard errors package
+//	errors.Errorf                        -> fmt.Errorf
+//
+// where errors is github.com/pkg/errors. Note that, unlike fmt.Errorf, werr returns nil
+// when wrapping a nil error. Constructs that cannot be converted safely, such as %w in
+// the middle of a format or uses of pkg/errors types, are left as is and reported on
+// standard error, in which case the exit status is 1.
+//
+// The flags are:
+//
+//	-l  list files whose content would change
+//	-w  write the result to the source file instead of standard output
+//
+// Directories are processed recursively, skipping vendor, testdata and hidden directories.
+// Without paths, standard input is processed.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	list  = flag.Bool("l", false, "list files whose content would change")
+	write = flag.Bool("w", false, "write result to the source file instead of stdout")
+)
+
+var errUnconverted = errors.New("some constructs were not converted")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: werrmigrate [flags] [path ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(flag.Args(), os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUnconverted) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+
+		os.Exit(1)
+	}
+}
+
+func run(paths []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(paths) == 0 {
+		if *write || *list {
+			return errors.New("cannot use -w or -l with standard input")
+		}
+
+		src, err := io.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("read stdin: %w", err)
+		}
+
+		return processFile("<standard input>", src, stdout, stderr)
+	}
+
+	var unconverted bool
+
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if d.IsDir() {
+				if name := d.Name(); path != "." && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+					return filepath.SkipDir
+				}
+
+				return nil
+			}
+
+			if !strings.HasSuffix(path, ".go") {
+				return nil
+			}
+
+			src, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read: %w", err)
+			}
+
+			err = processFile(path, src, stdout, stderr)
+			if errors.Is(err, errUnconverted) {
+				unconverted = true
+
+				return nil
+			}
+
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if unconverted {
+		return errUnconverted
+	}
+
+	return nil
+}
+
+func processFile(filename string, src []byte, stdout, stderr io.Writer) error {
+	out, issues, err := migrate(filename, src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	for _, i := range issues {
+		fmt.Fprintln(stderr, i)
+	}
+
+	changed := !bytes.Equal(src, out)
+
+	switch {
+	case *list:
+		if changed {
+			fmt.Fprintln(stdout, filename)
+		}
+	case *write:
+		if changed {
+			if err := os.WriteFile(filename, out, 0o600); err != nil { //nolint: mnd
+				return fmt.Errorf("write: %w", err)
+			}
+		}
+	default:
+		if _, err := stdout.Write(out); err != nil {
+			return fmt.Errorf("write: %w", err)
+		}
+	}
+
+	if len(issues) > 0 {
+		return errUnconverted
+	}
+
+	return nil
+}
@@ -0,0 +1,734 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	werrPath      = "github.com/safeblock-dev/werr"
+	pkgErrorsPath = "github.com/pkg/errors"
+)
+
+// issue is a construct that cannot be converted safely.
+type issue struct {
+	pos    token.Position
+	reason string
+}
+
+func (i issue) String() string {
+	return i.pos.String() + ": " + i.reason
+}
+
+// edit replaces src[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// migrator rewrites a single file.
+type migrator struct {
+	fset   *token.FileSet
+	src    []byte
+	edits  []edit
+	issues []issue
+
+	fmtName       string // fmtName is the name of the imported fmt package, if any.
+	pkgErrorsName string // pkgErrorsName is the name of the imported github.com/pkg/errors package, if any.
+	stdErrorsName string // stdErrorsName is the name under which std errors is referenced after migration.
+	werrName      string // werrName is the name under which werr is referenced after migration.
+
+	pkgErrorsKept bool // pkgErrorsKept reports whether some use of github.com/pkg/errors was not converted.
+}
+
+// migrate rewrites fmt.Errorf wrapping and github.com/pkg/errors calls in the file to werr.
+// It returns the formatted result and the constructs it could not convert.
+func migrate(filename string, src []byte) ([]byte, []issue, error) {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse: %w", err)
+	}
+
+	m := &migrator{
+		fset:          fset,
+		src:           src,
+		fmtName:       importName(file, "fmt"),
+		pkgErrorsName: importName(file, pkgErrorsPath),
+		stdErrorsName: importName(file, "errors"),
+		werrName:      importName(file, werrPath),
+	}
+
+	if m.werrName == "" {
+		m.werrName = "werr"
+	}
+
+	if m.stdErrorsName == "" {
+		m.stdErrorsName = "errors"
+	}
+
+	if m.fmtName == "" && m.pkgErrorsName == "" {
+		return src, nil, nil
+	}
+
+	m.rewriteCalls(file)
+
+	if len(m.edits) == 0 {
+		return src, m.issues, nil
+	}
+
+	out := apply(src, m.edits)
+
+	// Imports are fixed on the rewritten file, where the remaining uses are known.
+	file, err = parser.ParseFile(fset, filename, out, parser.ParseComments)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse rewritten source: %w", err)
+	}
+
+	m.src, m.edits = out, nil
+	m.fixImports(file)
+
+	out, err = format.Source(apply(out, m.edits))
+	if err != nil {
+		return nil, nil, fmt.Errorf("format: %w", err)
+	}
+
+	return out, m.issues, nil
+}
+
+// rewriteCalls replaces supported calls. Calls are processed innermost first,
+// so that nested calls end up rewritten in the text of the outer replacement.
+func (m *migrator) rewriteCalls(file *ast.File) {
+	var stack []ast.Node
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if node != nil {
+			stack = append(stack, node)
+
+			return true
+		}
+
+		node, stack = stack[len(stack)-1], stack[:len(stack)-1]
+
+		switch n := node.(type) {
+		case *ast.CallExpr:
+			m.rewriteCall(n)
+		case *ast.SelectorExpr:
+			// Called functions are handled by rewriteCall, other uses such as types are reported.
+			if call, ok := stack[len(stack)-1].(*ast.CallExpr); ok && call.Fun == n {
+				break
+			}
+
+			if m.isPkg(n, m.pkgErrorsName) {
+				m.report(n, "cannot convert use of "+pkgErrorsPath+"."+n.Sel.Name)
+			}
+		}
+
+		return true
+	})
+}
+
+func (m *migrator) rewriteCall(call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+
+	switch {
+	case m.isPkg(sel, m.fmtName) && sel.Sel.Name == "Errorf":
+		m.rewriteErrorf(call)
+	case m.isPkg(sel, m.pkgErrorsName):
+		m.rewritePkgErrors(call, sel.Sel.Name)
+	}
+}
+
+// rewriteErrorf converts fmt.Errorf("doing x: %w", err) into werr.Wrapf(err, "doing x").
+// Calls without %w create new errors and are left as is.
+func (m *migrator) rewriteErrorf(call *ast.CallExpr) {
+	if len(call.Args) == 0 {
+		return
+	}
+
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		m.report(call, "cannot convert fmt.Errorf with a non-constant format")
+
+		return
+	}
+
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil || !strings.Contains(format, "%w") {
+		return
+	}
+
+	prefix, ok := wrapPrefix(format)
+	if !ok || call.Ellipsis.IsValid() || len(call.Args) < 2 {
+		m.report(call, "cannot convert fmt.Errorf: %w must be the last verb at the end of the format")
+
+		return
+	}
+
+	args := call.Args[1:]
+	errArg := args[len(args)-1]
+	args = args[:len(args)-1]
+
+	if prefix == "" && len(args) == 0 {
+		m.replace(call, m.werrName+".Wrap("+m.text(errArg)+")")
+
+		return
+	}
+
+	m.replace(call, m.werrName+".Wrapf("+m.text(errArg)+", "+quote(prefix, lit.Value)+m.texts(args)+")")
+}
+
+// rewritePkgErrors converts a call to a github.com/pkg/errors function.
+func (m *migrator) rewritePkgErrors(call *ast.CallExpr, name string) {
+	args := call.Args
+
+	switch name {
+	case "Wrap", "WithMessage":
+		if len(args) != 2 { //nolint: mnd
+			break
+		}
+
+		msg := `"%s", ` + m.text(args[1])
+		if lit, ok := args[1].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			msg = strings.ReplaceAll(lit.Value, "%", "%%")
+		}
+
+		m.replace(call, m.werrName+".Wrapf("+m.text(args[0])+", "+msg+")")
+
+		return
+	case "Wrapf", "WithMessagef":
+		if len(args) < 2 { //nolint: mnd
+			break
+		}
+
+		m.replace(call, m.werrName+".Wrapf("+m.text(args[0])+m.texts(args[1:])+m.ellipsis(call)+")")
+
+		return
+	case "WithStack":
+		if len(args) != 1 {
+			break
+		}
+
+		m.replace(call, m.werrName+".Wrap("+m.text(args[0])+")")
+
+		return
+	case "Cause":
+		if len(args) != 1 {
+			break
+		}
+
+		m.replace(call, m.werrName+".Cause("+m.text(args[0])+")")
+
+		return
+	case "New", "Is", "As", "Unwrap":
+		m.replace(call, m.stdErrorsName+"."+name+"("+strings.TrimPrefix(m.texts(args), ", ")+m.ellipsis(call)+")")
+
+		return
+	case "Errorf":
+		name := m.fmtName
+		if name == "" {
+			name = "fmt"
+		}
+
+		m.replace(call, name+".Errorf("+strings.TrimPrefix(m.texts(args), ", ")+m.ellipsis(call)+")")
+
+		return
+	}
+
+	m.report(call, "cannot convert call to "+pkgErrorsPath+"."+name)
+}
+
+// fixImports adds the imports used by the rewritten calls and removes the imports that are no
+// longer used. Like astutil.AddNamedImport and astutil.DeleteNamedImport, it edits only the
+// affected specs, so the grouping and the comments of the other imports are kept.
+func (m *migrator) fixImports(file *ast.File) {
+	used := usedNames(file)
+	drop := make(map[*ast.ImportSpec]bool)
+
+	var add []string
+
+	for _, spec := range file.Imports {
+		switch path, _ := strconv.Unquote(spec.Path.Value); path {
+		case "fmt":
+			drop[spec] = !used[m.fmtName]
+		case pkgErrorsPath:
+			if m.pkgErrorsKept {
+				continue
+			}
+
+			drop[spec] = true
+
+			if m.pkgErrorsName == "errors" && importName(file, "errors") == "" && used["errors"] {
+				// The name now refers to the standard errors package.
+				add = append(add, "errors")
+			}
+		}
+	}
+
+	if used[m.werrName] && importName(file, werrPath) == "" {
+		add = append(add, werrPath)
+	}
+
+	if used[m.stdErrorsName] && importName(file, "errors") == "" && m.pkgErrorsName != "errors" {
+		add = append(add, "errors")
+	}
+
+	if used["fmt"] && m.fmtName == "" {
+		add = append(add, "fmt")
+	}
+
+	target := m.deleteImports(file, drop)
+	m.addImports(file, target, drop, add)
+}
+
+// deleteImports removes the dropped specs, or their whole declaration if no other spec is left.
+// It returns the first parenthesized import declaration with specs left, if any.
+func (m *migrator) deleteImports(file *ast.File, drop map[*ast.ImportSpec]bool) *ast.GenDecl {
+	var target *ast.GenDecl
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		if dropsAll(gen, drop) {
+			m.deleteLines(gen.Doc, gen)
+
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			if s := spec.(*ast.ImportSpec); drop[s] { //nolint: forcetypeassert
+				m.deleteLines(s.Doc, s)
+			}
+		}
+
+		if target == nil && gen.Lparen.IsValid() {
+			target = gen
+		}
+	}
+
+	return target
+}
+
+// deleteLines records the removal of the lines of the node and of its doc comment.
+func (m *migrator) deleteLines(doc *ast.CommentGroup, node ast.Node) {
+	start := m.offset(node.Pos())
+	if doc != nil {
+		start = m.offset(doc.Pos())
+	}
+
+	start = bytes.LastIndexByte(m.src[:start], '\n') + 1
+	end := m.lineEnd(node.End()) + 1
+
+	if end > len(m.src) {
+		end = len(m.src)
+	}
+
+	m.edits = append(m.edits, edit{start: start, end: end})
+}
+
+// dropsAll reports whether all the specs of the import declaration are dropped.
+func dropsAll(decl *ast.GenDecl, drop map[*ast.ImportSpec]bool) bool {
+	for _, spec := range decl.Specs {
+		if !drop[spec.(*ast.ImportSpec)] { //nolint: forcetypeassert
+			return false
+		}
+	}
+
+	return true
+}
+
+// lineEnd returns the offset of the end of the line containing pos.
+func (m *migrator) lineEnd(pos token.Pos) int {
+	offset := m.offset(pos)
+	if idx := bytes.IndexByte(m.src[offset:], '\n'); idx >= 0 {
+		return offset + idx
+	}
+
+	return len(m.src)
+}
+
+// addImports records the addition of the imports to the declaration. A standard package goes
+// to the first group of standard packages and another package to the group sharing the longest
+// path prefix with it; a new group is started if there is none. Without a parenthesized
+// declaration, the imports are added in a new one after the last import declaration.
+func (m *migrator) addImports(file *ast.File, target *ast.GenDecl, drop map[*ast.ImportSpec]bool, paths []string) {
+	if len(paths) == 0 {
+		return
+	}
+
+	if target == nil {
+		m.addImportDecl(file, drop, paths)
+
+		return
+	}
+
+	groups := m.importGroups(target, drop)
+
+	// Insertions at the same offset are merged to keep their order.
+	var offsets []int
+
+	texts := make(map[int]string)
+	insert := func(offset int, text string) {
+		if _, ok := texts[offset]; !ok {
+			offsets = append(offsets, offset)
+		}
+
+		texts[offset] += text
+	}
+
+	var std, other []string
+
+	for _, path := range paths {
+		group := bestGroup(groups, path)
+
+		switch {
+		case group != nil:
+			insert(m.lineEnd(group[len(group)-1].End()), "\n\t"+strconv.Quote(path))
+		case isStd(path):
+			std = append(std, path)
+		default:
+			other = append(other, path)
+		}
+	}
+
+	if len(std) > 0 {
+		insert(m.offset(target.Lparen)+1, "\n\t"+quoteImports(std)+"\n")
+	}
+
+	if len(other) > 0 {
+		last := groups[len(groups)-1]
+		insert(m.lineEnd(last[len(last)-1].End()), "\n\n\t"+quoteImports(other))
+	}
+
+	for _, offset := range offsets {
+		m.edits = append(m.edits, edit{start: offset, end: offset, text: texts[offset]})
+	}
+}
+
+// importGroups returns the specs of the declaration left after the removals,
+// split into the groups separated by blank lines.
+func (m *migrator) importGroups(decl *ast.GenDecl, drop map[*ast.ImportSpec]bool) [][]*ast.ImportSpec {
+	var (
+		groups   [][]*ast.ImportSpec
+		prevLine int
+	)
+
+	for _, spec := range decl.Specs {
+		s := spec.(*ast.ImportSpec) //nolint: forcetypeassert
+		if drop[s] {
+			continue
+		}
+
+		first := s.Pos()
+		if s.Doc != nil {
+			first = s.Doc.Pos()
+		}
+
+		if len(groups) == 0 || m.fset.Position(first).Line > prevLine+1 {
+			groups = append(groups, nil)
+		}
+
+		groups[len(groups)-1] = append(groups[len(groups)-1], s)
+		prevLine = m.fset.Position(s.End()).Line
+	}
+
+	return groups
+}
+
+// addImportDecl records the addition of a declaration importing the packages, grouped into
+// standard and other packages, after the last import declaration left or the package clause.
+func (m *migrator) addImportDecl(file *ast.File, drop map[*ast.ImportSpec]bool, paths []string) {
+	var std, other []string
+
+	for _, path := range paths {
+		if isStd(path) {
+			std = append(std, path)
+		} else {
+			other = append(other, path)
+		}
+	}
+
+	block := quoteImports(std)
+	if len(std) > 0 && len(other) > 0 {
+		block += "\n"
+	}
+
+	if len(other) > 0 {
+		block += "\n\t" + quoteImports(other)
+	}
+
+	if len(std) > 0 {
+		block = "\t" + block
+	}
+
+	pos := file.Name.End()
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT && !dropsAll(gen, drop) {
+			pos = gen.End()
+		}
+	}
+
+	offset := m.offset(pos)
+	m.edits = append(m.edits, edit{start: offset, end: offset, text: "\n\nimport (\n" + block + "\n)"})
+}
+
+// bestGroup returns the group of imports a package is added to: the first group of standard
+// packages for a standard package, and the group of other packages sharing the longest path
+// prefix with it otherwise. It returns nil if there is no such group.
+func bestGroup(groups [][]*ast.ImportSpec, path string) []*ast.ImportSpec {
+	var (
+		best   []*ast.ImportSpec
+		length = -1
+	)
+
+	for _, group := range groups {
+		first, _ := strconv.Unquote(group[0].Path.Value)
+		if isStd(first) != isStd(path) {
+			continue
+		}
+
+		if isStd(path) {
+			return group
+		}
+
+		for _, spec := range group {
+			p, _ := strconv.Unquote(spec.Path.Value)
+			if n := commonPrefix(p, path); n > length {
+				best, length = group, n
+			}
+		}
+	}
+
+	return best
+}
+
+// commonPrefix returns the number of leading path elements shared by the import paths.
+func commonPrefix(a, b string) int {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+
+	n := 0
+	for n < len(as) && n < len(bs) && as[n] == bs[n] {
+		n++
+	}
+
+	return n
+}
+
+// isStd reports whether the import path is a standard package, without a dot in its first element.
+func isStd(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".") //nolint: mnd
+}
+
+// quoteImports returns the quoted import paths on separate lines indented by a tab, but the first one.
+func quoteImports(paths []string) string {
+	quoted := make([]string, len(paths))
+	for i, path := range paths {
+		quoted[i] = strconv.Quote(path)
+	}
+
+	return strings.Join(quoted, "\n\t")
+}
+
+// replace records the replacement of the node, dropping the edits of nested nodes
+// which are expected to be part of the replacement text.
+func (m *migrator) replace(node ast.Node, text string) {
+	e := m.edit(node, text)
+
+	edits := m.edits[:0]
+
+	for _, other := range m.edits {
+		if other.start < e.start || other.end > e.end {
+			edits = append(edits, other)
+		}
+	}
+
+	m.edits = append(edits, e)
+}
+
+func (m *migrator) edit(node ast.Node, text string) edit {
+	return edit{start: m.offset(node.Pos()), end: m.offset(node.End()), text: text}
+}
+
+// text returns the source text of the node with the edits of nested nodes applied.
+func (m *migrator) text(node ast.Node) string {
+	start, end := m.offset(node.Pos()), m.offset(node.End())
+
+	var nested []edit
+
+	for _, e := range m.edits {
+		if e.start >= start && e.end <= end {
+			nested = append(nested, edit{start: e.start - start, end: e.end - start, text: e.text})
+		}
+	}
+
+	return string(apply(m.src[start:end], nested))
+}
+
+// texts returns the source text of the nodes, each preceded by ", ".
+func (m *migrator) texts(nodes []ast.Expr) string {
+	var b strings.Builder
+
+	for _, node := range nodes {
+		b.WriteString(", ")
+		b.WriteString(m.text(node))
+	}
+
+	return b.String()
+}
+
+func (m *migrator) ellipsis(call *ast.CallExpr) string {
+	if call.Ellipsis.IsValid() {
+		return "..."
+	}
+
+	return ""
+}
+
+func (m *migrator) isPkg(sel *ast.SelectorExpr, name string) bool {
+	id, ok := sel.X.(*ast.Ident)
+
+	return ok && name != "" && id.Name == name
+}
+
+func (m *migrator) report(node ast.Node, reason string) {
+	if m.isPkgErrors(node) {
+		m.pkgErrorsKept = true
+	}
+
+	m.issues = append(m.issues, issue{pos: m.fset.Position(node.Pos()), reason: reason})
+}
+
+// isPkgErrors reports whether the node is a use of github.com/pkg/errors.
+func (m *migrator) isPkgErrors(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.CallExpr:
+		sel, ok := n.Fun.(*ast.SelectorExpr)
+
+		return ok && m.isPkg(sel, m.pkgErrorsName)
+	case *ast.SelectorExpr:
+		return m.isPkg(n, m.pkgErrorsName)
+	}
+
+	return false
+}
+
+func (m *migrator) offset(pos token.Pos) int {
+	return m.fset.Position(pos).Offset
+}
+
+// apply returns src with the non-overlapping edits applied.
+func apply(src []byte, edits []edit) []byte {
+	sorted := append([]edit(nil), edits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start < sorted[j].start })
+
+	var b bytes.Buffer
+
+	last := 0
+
+	for _, e := range sorted {
+		b.Write(src[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+
+	b.Write(src[last:])
+
+	return b.Bytes()
+}
+
+// importName returns the name under which the file imports the package, or an empty string.
+func importName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p != path {
+			continue
+		}
+
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+
+	return ""
+}
+
+// usedNames returns the identifiers used as qualifiers of selector expressions.
+func usedNames(file *ast.File) map[string]bool {
+	used := make(map[string]bool)
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		if sel, ok := node.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+
+		return true
+	})
+
+	return used
+}
+
+// wrapPrefix returns the message of a format whose only %w verb is the last thing in it,
+// with the ": " separator trimmed. It reports false for other formats, and for formats
+// using explicit argument indexes or star widths.
+func wrapPrefix(format string) (string, bool) {
+	idx := -1
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// Find the verb, skipping flags, width and precision.
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+
+		if j >= len(format) || format[j] == '[' || format[j] == '*' {
+			return "", false
+		}
+
+		if format[j] == 'w' {
+			if idx >= 0 {
+				return "", false
+			}
+
+			idx = i
+		}
+
+		i = j
+	}
+
+	if idx < 0 || idx+2 != len(format) {
+		return "", false
+	}
+
+	return strings.TrimRight(format[:idx], ": "), true
+}
+
+// quote quotes the string in the style of the original literal.
+func quote(s, original string) string {
+	if strings.HasPrefix(original, "`") && !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+
+	return strconv.Quote(s)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files") //nolint: gochecknoglobals
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		input := input
+		name := strings.TrimSuffix(filepath.Base(input), ".input")
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			src, err := os.ReadFile(input)
+			require.NoError(t, err)
+
+			out, issues, err := migrate(input, src)
+			require.NoError(t, err)
+
+			var report strings.Builder
+			for _, i := range issues {
+				report.WriteString(strconv.Itoa(i.pos.Line) + ":" + strconv.Itoa(i.pos.Column) + ": " + i.reason + "\n")
+			}
+
+			golden := strings.TrimSuffix(input, ".input")
+			if *update {
+				require.NoError(t, os.WriteFile(golden+".golden", out, 0o600))
+
+				if report.Len() > 0 {
+					require.NoError(t, os.WriteFile(golden+".issues", []byte(report.String()), 0o600))
+				}
+			}
+
+			exp, err := os.ReadFile(golden + ".golden")
+			require.NoError(t, err)
+			require.Equal(t, string(exp), string(out))
+
+			expIssues, err := os.ReadFile(golden + ".issues")
+			if os.IsNotExist(err) {
+				expIssues, err = nil, nil
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, string(expIssues), report.String())
+		})
+	}
+}
+
+func TestWrapPrefix(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		format string
+		prefix string
+		ok     bool
+	}{
+		{format: "doing x: %w", prefix: "doing x", ok: true},
+		{format: "%w", prefix: "", ok: true},
+		{format: "load %d %w", prefix: "load %d", ok: true},
+		{format: "100%% done: %w", prefix: "100%% done", ok: true},
+		{format: "%w: doing x", ok: false},
+		{format: "%w %w", ok: false},
+		{format: "%[2]d: %[1]w", ok: false},
+		{format: "%*d: %w", ok: false},
+	}
+
+	for _, testCase := range testCases {
+		tt := testCase
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+
+			prefix, ok := wrapPrefix(tt.format)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.prefix, prefix)
+		})
+	}
+}
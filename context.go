@@ -0,0 +1,123 @@
+package werr
+
+import "context"
+
+// ContextFieldFn extracts a request-scoped value from a context, e.g. a request ID.
+// It returns nil if the context holds no value.
+type ContextFieldFn func(ctx context.Context) any
+
+// contextField is a registered context extractor.
+type contextField struct {
+	key string
+	fn  ContextFieldFn
+}
+
+var _contextFields contextFields //nolint: gochecknoglobals
+
+// contextFields is the list of registered context extractors, in registration order.
+type contextFields struct {
+	cowList[contextField]
+}
+
+// RegisterContextField registers a function extracting a value recorded under key
+// by WrapCtx, e.g. the request ID, the trace and span IDs or the tenant.
+// Fields are recorded in registration order. Registering the same key again replaces
+// the previous extractor; a nil fn removes it.
+// Example: werr.RegisterContextField("request_id", func(ctx context.Context) any { return ctx.Value(requestIDKey{}) }).
+func RegisterContextField(key string, fn ContextFieldFn) {
+	_contextFields.set(key, fn)
+}
+
+// set replaces, appends or removes the extractor registered under key.
+func (c *contextFields) set(key string, fn ContextFieldFn) {
+	c.update(func(list []contextField) []contextField {
+		for i, f := range list {
+			if f.key != key {
+				continue
+			}
+
+			if fn == nil {
+				return append(list[:i], list[i+1:]...)
+			}
+
+			list[i].fn = fn
+
+			return list
+		}
+
+		if fn != nil {
+			list = append(list, contextField{key: key, fn: fn})
+		}
+
+		return list
+	})
+}
+
+// fields returns the values of the registered extractors found in ctx, skipping nil values
+// and keys already recorded on the inner layers of err.
+func (c *contextFields) fields(ctx context.Context, err error) []Field {
+	list := c.load()
+	if len(list) == 0 || ctx == nil {
+		return nil
+	}
+
+	var fields []Field
+
+	for _, f := range list {
+		v := extract(f.fn, ctx)
+		if v == nil || recorded(err, f.key) {
+			continue
+		}
+
+		fields = append(fields, Field{Key: f.key, Value: v})
+	}
+
+	return fields
+}
+
+// extract runs a single extractor, recovering from its panic.
+func extract(fn ContextFieldFn, ctx context.Context) (v any) {
+	defer func() { _ = recover() }()
+
+	return fn(ctx)
+}
+
+// recorded reports whether a field with the key is recorded on a layer of the chain,
+// so that a value wrapped several times within a request is rendered once.
+func recorded(err error, key string) bool {
+	for {
+		e, ok := err.(Error) //nolint: errorlint
+		if !ok {
+			return false
+		}
+
+		for _, f := range e.Args() {
+			if f.Key == key {
+				return true
+			}
+		}
+
+		err = e.err
+	}
+}
+
+// WrapCtx takes a context and an error, and returns a new wrapped error.
+// If the input error (err) is nil, the function returns nil.
+// The values of the fields registered with RegisterContextField are extracted from ctx
+// and recorded as named arguments: they are rendered as "key=value" pairs and are
+// available through Error.Args and Frame.Args. A key already recorded on an inner layer
+// of the chain is not recorded again.
+func WrapCtx(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, "")
+	if fields := _contextFields.fields(ctx, err); len(fields) > 0 {
+		e.args = &fields
+	}
+
+	_wrapHooks.call(e)
+
+	return e
+}
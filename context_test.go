@@ -0,0 +1,103 @@
+package werr_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+type (
+	requestIDKey struct{}
+	tenantKey    struct{}
+)
+
+// nolint: paralleltest
+func TestWrapCtx(t *testing.T) {
+	originalErr := errors.New("original error")
+
+	werr.RegisterContextField("request_id", func(ctx context.Context) any { return ctx.Value(requestIDKey{}) })
+	werr.RegisterContextField("tenant", func(ctx context.Context) any { return ctx.Value(tenantKey{}) })
+
+	defer werr.RegisterContextField("request_id", nil)
+	defer werr.RegisterContextField("tenant", nil)
+
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "r-42")
+	ctx = context.WithValue(ctx, tenantKey{}, "acme")
+
+	t.Run("when nil", func(t *testing.T) {
+		require.NoError(t, werr.WrapCtx(ctx, nil))
+	})
+
+	t.Run("with context fields", func(t *testing.T) {
+		err := werr.WrapCtx(ctx, originalErr)
+		require.ErrorIs(t, err, originalErr)
+
+		var e werr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, "github.com/safeblock-dev/werr_test.TestWrapCtx.func4", e.FuncName())
+		require.Equal(t, []werr.Field{werr.Arg("request_id", "r-42"), werr.Arg("tenant", "acme")}, e.Args())
+		require.Equal(t, e.Args(), e.Frame().Args)
+		require.Contains(t, err.Error(), "func4()\trequest_id=r-42 tenant=acme\noriginal error")
+	})
+
+	t.Run("when value missing", func(t *testing.T) {
+		err := werr.WrapCtx(context.WithValue(context.Background(), tenantKey{}, "acme"), originalErr)
+
+		var e werr.Error
+		require.ErrorAs(t, err, &e)
+		require.Equal(t, []werr.Field{werr.Arg("tenant", "acme")}, e.Args())
+	})
+
+	t.Run("when recorded on inner layer", func(t *testing.T) {
+		err := werr.WrapCtx(ctx, werr.Wrap(werr.WrapCtx(ctx, originalErr)))
+
+		var e werr.Error
+		require.ErrorAs(t, err, &e)
+		require.Nil(t, e.Args())
+		require.Equal(t, 1, strings.Count(err.Error(), "request_id=r-42"))
+	})
+
+	t.Run("when extractor panics", func(t *testing.T) {
+		werr.RegisterContextField("request_id", func(context.Context) any { panic("boom") })
+
+		var e werr.Error
+		require.ErrorAs(t, werr.WrapCtx(ctx, originalErr), &e)
+		require.Equal(t, []werr.Field{werr.Arg("tenant", "acme")}, e.Args())
+	})
+
+	t.Run("when replaced keeps order", func(t *testing.T) {
+		werr.RegisterContextField("request_id", func(context.Context) any { return "fixed" })
+
+		var e werr.Error
+		require.ErrorAs(t, werr.WrapCtx(ctx, originalErr), &e)
+		require.Equal(t, []werr.Field{werr.Arg("request_id", "fixed"), werr.Arg("tenant", "acme")}, e.Args())
+	})
+
+	t.Run("when unregistered", func(t *testing.T) {
+		werr.RegisterContextField("request_id", nil)
+		werr.RegisterContextField("tenant", nil)
+
+		var e werr.Error
+		require.ErrorAs(t, werr.WrapCtx(ctx, originalErr), &e)
+		require.Nil(t, e.Args())
+		require.NotNil(t, werr.WrapCtx(nil, originalErr)) //nolint: staticcheck
+	})
+
+	t.Run("called hooks", func(t *testing.T) {
+		werr.RegisterContextField("tenant", func(ctx context.Context) any { return ctx.Value(tenantKey{}) })
+
+		var frames []werr.Frame
+
+		defer werr.OnWrap(func(frame werr.Frame, _ error) { frames = append(frames, frame) })()
+
+		_ = werr.WrapCtx(ctx, originalErr)
+
+		require.Len(t, frames, 1)
+		require.Equal(t, []werr.Field{werr.Arg("tenant", "acme")}, frames[0].Args)
+	})
+}
@@ -0,0 +1,37 @@
+package werr
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// cowList is a copy-on-write list for registries read on every wrap: reading it takes
+// a single atomic load and does not block updates, which are serialized.
+type cowList[T any] struct {
+	mu   sync.Mutex
+	list atomic.Pointer[[]T]
+}
+
+// load returns the current list, which must not be modified.
+func (l *cowList[T]) load() []T {
+	if list := l.list.Load(); list != nil {
+		return *list
+	}
+
+	return nil
+}
+
+// update replaces the list with the result of fn called with a copy of the current list.
+func (l *cowList[T]) update(fn func(list []T) []T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	list := fn(append([]T(nil), l.load()...))
+	if len(list) == 0 {
+		l.list.Store(nil)
+
+		return
+	}
+
+	l.list.Store(&list)
+}
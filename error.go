@@ -2,18 +2,24 @@ package werr
 
 // Error represents an error with additional context such as funcName, file, line, and msg.
 type Error struct {
-	funcName string // funcName represents the fully qualified function name ("<pkg>.<name>").
-	file     string // file is the file name where the error occurred.
-	line     int    // line is the line number in the file where the error occurred.
-	err      error  // err is the original error that was wrapped.
-	msg      string // msg is an optional message to provide additional context for the error.
+	funcName string   // funcName represents the fully qualified function name ("<pkg>.<name>").
+	file     string   // file is the file name where the error occurred.
+	line     int      // line is the line number in the file where the error occurred.
+	pc       uintptr  // pc is the program counter of the wrap site, as reported by runtime.Callers.
+	err      error    // err is the original error that was wrapped.
+	msg      string   // msg is an optional message to provide additional context for the error.
+	format   string   // format is the message template without arguments; it is empty when equal to msg.
+	rawMsg   string   // rawMsg is msg with secret values revealed; it is empty when equal to msg.
+	args     *[]Field // args holds named function arguments; a pointer keeps Error comparable.
+	public   string   // public is an optional user-facing message, see PublicMessage.
 }
 
 // newError creates a new wrapped error with caller information and an optional additional message.
-func newError(err error, msg string) error {
-	funcName, file, line := caller(defaultCallerSkip)
+func newError(err error, msg string) Error {
+	pc, funcName, file, line := caller(defaultCallerSkip)
 
 	return Error{
+		pc:       pc,
 		file:     file,
 		funcName: funcName,
 		line:     line,
@@ -24,12 +30,14 @@ func newError(err error, msg string) error {
 
 // Error returns a string representation of the wrapped error.
 func (e Error) Error() string {
-	return _defaultFormatter(e.file, e.line, e.funcName, e.err, e.msg)
+	return string(e.AppendTo(make([]byte, 0, e.sizeHint())))
 }
 
 // Format returns a custom formatted string representation of the wrapped error using a provided formatter function.
+// The function is called for this layer with the original inner error, which renders the inner layers
+// with the global formatter; Formatter{Fn: fn} applies fn to every layer instead.
 func (e Error) Format(fn FormatFn) string {
-	return fn(e.file, e.line, e.funcName, e.err, e.msg)
+	return fn(e.file, e.line, e.funcName, e.err, e.message(Redacted))
 }
 
 // Unwrap returns the underlying error wrapped by this structure.
@@ -68,6 +76,55 @@ func (e Error) FuncName() string {
 }
 
 // Message returns the additional message associated with the wrapped error.
+// Secret values in the message are redacted, see Secret.
 func (e Error) Message() string {
 	return e.msg
 }
+
+// Args returns the named function arguments recorded on the wrapped error, see WrapArgs.
+func (e Error) Args() []Field {
+	if e.args == nil {
+		return nil
+	}
+
+	return *e.args
+}
+
+// template returns the message without its arguments, see FingerprintMessages.
+func (e Error) template() string {
+	if e.format != "" {
+		return e.format
+	}
+
+	return e.msg
+}
+
+// text returns the additional message without the named arguments, rendered in the given redaction mode.
+func (e Error) text(mode Redaction) string {
+	if mode == Unredacted && e.rawMsg != "" {
+		return e.rawMsg
+	}
+
+	return e.msg
+}
+
+// message returns the additional message followed by the named arguments,
+// rendered in the given redaction mode.
+func (e Error) message(mode Redaction) string {
+	msg := e.text(mode)
+	if e.args == nil {
+		return msg
+	}
+
+	args := formatFields(*e.args, mode)
+	if msg == "" {
+		return args
+	}
+
+	return msg + " " + args
+}
+
+// Public returns the user-facing message attached to this layer of the error, if any.
+func (e Error) Public() string {
+	return e.public
+}
@@ -0,0 +1,120 @@
+package werr
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field is a named value recorded on a wrapped error.
+type Field struct {
+	Key   string // Key is the name of the value, e.g. a function argument name.
+	Value any    // Value is the recorded value; it may be marked with Secret or Safe.
+}
+
+// Arg returns a Field describing a named function argument.
+// Example: werr.WrapArgs(err, werr.Arg("userID", userID)).
+func Arg(name string, v any) Field {
+	return Field{Key: name, Value: v}
+}
+
+// AppendValue appends the value of the field rendered with the %+v verb
+// in the given redaction mode, without quoting.
+func (f Field) AppendValue(dst []byte, mode Redaction) []byte {
+	return appendValue(dst, f.Value, mode)
+}
+
+// quotedChars are the characters that make a field value quoted.
+const quotedChars = " \t\n\"="
+
+// formatFields renders fields as space-separated "key=value" pairs in the given redaction mode.
+// Values containing spaces, quotes or "=" are quoted.
+func formatFields(fields []Field, mode Redaction) string {
+	return string(appendFields(nil, fields, mode))
+}
+
+// appendFields appends the fields rendered by formatFields to dst.
+func appendFields(dst []byte, fields []Field, mode Redaction) []byte {
+	for i, f := range fields {
+		if i > 0 {
+			dst = append(dst, ' ')
+		}
+
+		dst = append(dst, f.Key...)
+		dst = append(dst, '=')
+
+		v, _ := redactValue(f.Value, mode)
+
+		// Strings are checked before appending, so that quoting them does not allocate.
+		if s, ok := v.(string); ok {
+			if strings.ContainsAny(s, quotedChars) {
+				dst = strconv.AppendQuote(dst, s)
+			} else {
+				dst = append(dst, s...)
+			}
+
+			continue
+		}
+
+		start := len(dst)
+		if dst = appendRedacted(dst, v); bytes.ContainsAny(dst[start:], quotedChars) {
+			dst = strconv.AppendQuote(dst[:start], string(dst[start:]))
+		}
+	}
+
+	return dst
+}
+
+// formatValue renders a field value with the %+v verb in the given redaction mode.
+func formatValue(v any, mode Redaction) string {
+	return string(appendValue(nil, v, mode))
+}
+
+// appendValue appends a field value rendered with the %+v verb in the given redaction mode.
+func appendValue(dst []byte, v any, mode Redaction) []byte {
+	v, _ = redactValue(v, mode)
+
+	return appendRedacted(dst, v)
+}
+
+// appendRedacted appends a value prepared by redactValue rendered with the %+v verb.
+// Strings, booleans and numbers are appended without going through the fmt package.
+func appendRedacted(dst []byte, v any) []byte {
+	switch val := v.(type) {
+	case secretValue:
+		return append(dst, RedactedMarker...)
+	case redactedValue:
+		return append(dst, val...)
+	case string:
+		return append(dst, val...)
+	case bool:
+		return strconv.AppendBool(dst, val)
+	case int:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case int8:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case int16:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case int32:
+		return strconv.AppendInt(dst, int64(val), 10)
+	case int64:
+		return strconv.AppendInt(dst, val, 10)
+	case uint:
+		return strconv.AppendUint(dst, uint64(val), 10)
+	case uint8:
+		return strconv.AppendUint(dst, uint64(val), 10)
+	case uint16:
+		return strconv.AppendUint(dst, uint64(val), 10)
+	case uint32:
+		return strconv.AppendUint(dst, uint64(val), 10)
+	case uint64:
+		return strconv.AppendUint(dst, val, 10)
+	case float64:
+		return strconv.AppendFloat(dst, val, 'g', -1, 64)
+	case float32:
+		return strconv.AppendFloat(dst, float64(val), 'g', -1, 32)
+	}
+
+	return fmt.Appendf(dst, "%+v", v)
+}
@@ -0,0 +1,80 @@
+package werr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatFields(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when primitives", func(t *testing.T) {
+		t.Parallel()
+
+		fields := []Field{Arg("userID", 42), Arg("name", "john"), Arg("active", true)}
+		require.Equal(t, "userID=42 name=john active=true", formatFields(fields, Redacted))
+	})
+
+	t.Run("when value needs quoting", func(t *testing.T) {
+		t.Parallel()
+
+		fields := []Field{Arg("name", "John Doe"), Arg("query", "a=b")}
+		require.Equal(t, `name="John Doe" query="a=b"`, formatFields(fields, Redacted))
+	})
+
+	t.Run("when secret", func(t *testing.T) {
+		t.Parallel()
+
+		fields := []Field{Arg("login", "admin"), Arg("password", Secret("hunter2"))}
+		require.Equal(t, "login=admin password=‹×›", formatFields(fields, Redacted))
+		require.Equal(t, "login=admin password=hunter2", formatFields(fields, Unredacted))
+	})
+}
+
+func TestError_message(t *testing.T) {
+	t.Parallel()
+
+	args := []Field{Arg("userID", 42), Arg("token", Secret("t0k3n"))}
+
+	t.Run("with message and args", func(t *testing.T) {
+		t.Parallel()
+
+		e := Error{msg: "load user", args: &args}
+		require.Equal(t, "load user userID=42 token=‹×›", e.message(Redacted))
+		require.Equal(t, "load user userID=42 token=t0k3n", e.message(Unredacted))
+	})
+
+	t.Run("with args only", func(t *testing.T) {
+		t.Parallel()
+
+		e := Error{args: &args}
+		require.Equal(t, "userID=42 token=‹×›", e.message(Redacted))
+	})
+
+	t.Run("in default format", func(t *testing.T) {
+		t.Parallel()
+
+		e := Error{file: "main.go", funcName: "main.main", line: 42, err: errors.New("no rows"), args: &args}
+		require.Equal(t, "main/main.go:42\tmain()\tuserID=42 token=‹×›\nno rows", e.Error())
+	})
+}
+
+func TestField_AppendValue(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when value has spaces", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "name=John Doe", string(Arg("name", "John Doe").AppendValue([]byte("name="), Redacted)))
+	})
+
+	t.Run("when secret", func(t *testing.T) {
+		t.Parallel()
+
+		f := Arg("token", Secret("t0k3n"))
+		require.Equal(t, RedactedMarker, string(f.AppendValue(nil, Redacted)))
+		require.Equal(t, "t0k3n", string(f.AppendValue(nil, Unredacted)))
+	})
+}
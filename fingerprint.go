@@ -0,0 +1,75 @@
+package werr
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strconv"
+)
+
+// FingerprintOption increases the sensitivity of Fingerprint.
+// Options are bit flags and can be combined with "|".
+type FingerprintOption uint8
+
+const (
+	// FingerprintLines includes the line numbers of wrap sites.
+	FingerprintLines FingerprintOption = 1 << iota
+	// FingerprintMessages includes the message templates of wrap sites, without their arguments.
+	FingerprintMessages
+	// FingerprintCauseText includes the text of the root cause, not only its type.
+	FingerprintCauseText
+)
+
+// Fingerprint returns a stable key for grouping occurrences of the same error,
+// e.g. in alerts and dashboards. By default it is computed from the sequence of
+// wrap-site function names and the type of the root cause, so line numbers,
+// messages and message arguments do not affect it. Options make it more sensitive.
+// If the error is nil, the function returns an empty string.
+//
+// Example output: "9f86d081884c7d65".
+func Fingerprint(err error, opts ...FingerprintOption) string {
+	if err == nil {
+		return ""
+	}
+
+	var mode FingerprintOption
+	for _, opt := range opts {
+		mode |= opt
+	}
+
+	h := fnv.New64a()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+
+	cause := err
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		cause = err
+
+		e, ok := err.(Error) //nolint: errorlint
+		if !ok {
+			continue
+		}
+
+		write(e.funcName)
+
+		if mode&FingerprintLines != 0 {
+			write(strconv.Itoa(e.line))
+		}
+
+		if mode&FingerprintMessages != 0 {
+			write(e.template())
+		}
+	}
+
+	write(reflect.TypeOf(cause).String())
+
+	if mode&FingerprintCauseText != 0 {
+		write(cause.Error())
+	}
+
+	return fmt.Sprintf("%016x", h.Sum64())
+}
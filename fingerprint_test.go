@@ -0,0 +1,116 @@
+package werr_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+var errFingerprint = errors.New("fingerprint error")
+
+type fingerprintError struct{}
+
+func (fingerprintError) Error() string { return "original error" }
+
+func fingerprintLoad(id int) error {
+	return werr.Wrapf(errFingerprint, "load id=%d", id)
+}
+
+func fingerprintHandle(id int) error {
+	if id%2 == 0 {
+		return werr.Wrap(fingerprintLoad(id))
+	}
+
+	return werr.Wrap(fingerprintLoad(id)) // same function, different line
+}
+
+func fingerprintOther() error {
+	return werr.Wrapf(errFingerprint, "load id=%d", 1)
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ignores arguments and lines", func(t *testing.T) {
+		t.Parallel()
+
+		fp := werr.Fingerprint(fingerprintHandle(2))
+
+		require.Len(t, fp, 16)
+		require.Equal(t, fp, werr.Fingerprint(fingerprintHandle(3)))
+		require.Equal(t, fp, werr.Fingerprint(fingerprintHandle(4)))
+	})
+
+	t.Run("depends on wrap sites", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotEqual(t, werr.Fingerprint(fingerprintLoad(1)), werr.Fingerprint(fingerprintOther()))
+		require.NotEqual(t, werr.Fingerprint(fingerprintLoad(1)), werr.Fingerprint(fingerprintHandle(1)))
+	})
+
+	t.Run("depends on cause type", func(t *testing.T) {
+		t.Parallel()
+
+		err1 := werr.Wrap(errors.New("original error"))
+		err2 := werr.Wrap(fingerprintError{})
+
+		require.NotEqual(t, werr.Fingerprint(err1), werr.Fingerprint(err2))
+	})
+
+	t.Run("with lines", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotEqual(t,
+			werr.Fingerprint(fingerprintHandle(2), werr.FingerprintLines),
+			werr.Fingerprint(fingerprintHandle(3), werr.FingerprintLines),
+		)
+		require.Equal(t,
+			werr.Fingerprint(fingerprintHandle(2), werr.FingerprintLines),
+			werr.Fingerprint(fingerprintHandle(4), werr.FingerprintLines),
+		)
+	})
+
+	t.Run("with messages", func(t *testing.T) {
+		t.Parallel()
+
+		err1 := werr.Wrapf(errFingerprint, "load id=%d", 1)
+		err2 := werr.Wrapf(errFingerprint, "load id=%d", 2)
+		err3 := werr.Wrapf(errFingerprint, "save id=%d", 1)
+
+		fp1 := werr.Fingerprint(err1, werr.FingerprintMessages)
+		require.Equal(t, fp1, werr.Fingerprint(err2, werr.FingerprintMessages))
+		require.NotEqual(t, fp1, werr.Fingerprint(err3, werr.FingerprintMessages))
+		require.Equal(t, werr.Fingerprint(err1), werr.Fingerprint(err3))
+	})
+
+	t.Run("with cause text", func(t *testing.T) {
+		t.Parallel()
+
+		err1 := werr.Wrap(errors.New("original error 1"))
+		err2 := werr.Wrap(errors.New("original error 2"))
+
+		require.Equal(t, werr.Fingerprint(err1), werr.Fingerprint(err2))
+		require.NotEqual(t,
+			werr.Fingerprint(err1, werr.FingerprintCauseText),
+			werr.Fingerprint(err2, werr.FingerprintCauseText),
+		)
+	})
+
+	t.Run("when panic", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t,
+			werr.Fingerprint(werr.PanicToError("boom"), werr.FingerprintMessages),
+			werr.Fingerprint(werr.PanicToError("boom"), werr.FingerprintMessages),
+		)
+	})
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, werr.Fingerprint(nil))
+	})
+}
@@ -7,21 +7,16 @@ import (
 )
 
 // FormatFn defines a function signature for custom error formatting.
+// It is called for every wrapped layer, outermost first. When set with SetFormatter, err is the original
+// inner error of the layer, so err.(werr.Error) matches inner werr layers. When applied by a Formatter,
+// err is a rendering of the inner layers with the formatter settings that unwraps to the original inner error.
 type FormatFn func(file string, line int, funcName string, err error, msg string) string
 
-var _defaultFormatter FormatFn = defaultFormatter //nolint: gochecknoglobals
+var _formatter ChainFormatter = defaultChainFormatter{} //nolint: gochecknoglobals
 
 // defaultFormatter provides a default formatting style for error messages.
 func defaultFormatter(file string, line int, funcName string, err error, msg string) string {
-	idx := strings.LastIndex(funcName, ".")
-	pkg := funcName[:idx]
-
-	var fn string
-	if len(funcName) > idx {
-		fn = funcName[idx+1:] + "()"
-	}
-
-	source := pkg + "/" + path.Base(file) + ":" + strconv.Itoa(line)
+	source, fn := location(file, line, funcName)
 
 	if msg != "" {
 		msg = "\t" + msg
@@ -30,7 +25,126 @@ func defaultFormatter(file string, line int, funcName string, err error, msg str
 	return source + "\t" + fn + msg + "\n" + err.Error()
 }
 
+// location returns the source location ("<pkg>/<file>:<line>") and the function ("<name>()")
+// of a wrap site, as printed by the default formatter.
+func location(file string, line int, funcName string) (string, string) {
+	idx := strings.LastIndex(funcName, ".")
+	pkg, fn := funcName, ""
+
+	if idx >= 0 {
+		pkg, fn = funcName[:idx], funcName[idx+1:]+"()"
+	}
+
+	return pkg + "/" + path.Base(file) + ":" + strconv.Itoa(line), fn
+}
+
 // SetFormatter allows setting a custom error formatting function.
+// A nil function restores the default formatter.
 func SetFormatter(fn FormatFn) {
-	_defaultFormatter = fn
+	if fn == nil {
+		_formatter = defaultChainFormatter{}
+
+		return
+	}
+
+	_formatter = fn
+}
+
+// SetChainFormatter allows setting a custom formatter rendering the whole error chain at once.
+// A nil formatter restores the default formatter.
+func SetChainFormatter(f ChainFormatter) {
+	if f == nil {
+		f = defaultChainFormatter{}
+	}
+
+	_formatter = f
+}
+
+// View selects which representation of an error a Formatter renders.
+type View uint8
+
+const (
+	// ViewInternal renders the full trace with locations and internal messages.
+	ViewInternal View = iota
+	// ViewPublic renders only the user-facing message, see PublicMessage.
+	ViewPublic
+)
+
+// Formatter renders errors according to its configuration.
+// The zero value renders the redacted internal trace using the global formatter.
+type Formatter struct {
+	View      View           // View selects the internal trace or the public message.
+	Redaction Redaction      // Redaction selects whether secret values are hidden.
+	Fn        FormatFn       // Fn is used for every wrapped layer instead of the global formatter, if set.
+	Chain     ChainFormatter // Chain renders the whole chain instead of the global formatter, if set; it takes precedence over Fn.
+}
+
+// Format returns the string representation of err selected by the formatter configuration.
+func (f Formatter) Format(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if f.View == ViewPublic {
+		return PublicMessage(err)
+	}
+
+	if f.Chain == nil && f.Fn == nil && f.Redaction == Redacted {
+		return err.Error()
+	}
+
+	e, ok := err.(Error) //nolint: errorlint
+	if !ok {
+		// The werr layers wrapped by another error, e.g. created by fmt.Errorf with %w,
+		// are rendered by the formatter after the message of the wrapping error.
+		msg, inner := splitWrapper(err)
+		if inner == nil {
+			return err.Error()
+		}
+
+		return joinWrapper(msg, f.Format(inner))
+	}
+
+	cf := f.Chain
+	if cf == nil && f.Fn != nil {
+		cf = f.Fn
+	}
+
+	if cf == nil {
+		cf = _formatter
+	}
+
+	c := e.chain(f.Redaction)
+
+	// The werr layers behind another error are rendered by the formatter as well,
+	// so their messages follow its redaction mode instead of the text of the error.
+	if msg, inner := splitWrapper(c.Cause); inner != nil {
+		c.Cause = &wrapperError{err: c.Cause, text: joinWrapper(msg, f.Format(inner))}
+	}
+
+	return string(cf.AppendChain(nil, c))
+}
+
+// wrapperError replaces the cause of a chain wrapping werr layers without being one,
+// with the inner layers rendered by a Formatter.
+type wrapperError struct {
+	err  error
+	text string
+}
+
+func (e *wrapperError) Error() string {
+	return e.text
+}
+
+func (e *wrapperError) Unwrap() error {
+	return e.err
+}
+
+// joinWrapper returns the text of an error wrapping another one, as written by fmt.Errorf("msg: %w").
+func joinWrapper(msg, inner string) string {
+	if msg == "" {
+		return inner
+	}
+
+	return msg + ": " + inner
 }
@@ -22,14 +22,19 @@ func TestDefaultFormatter(t *testing.T) {
 
 // nolint: paralleltest
 func TestSetFormatter(t *testing.T) {
+	defer SetFormatter(nil)
+
+	err := Error{
+		file:     "file.go",
+		line:     42,
+		funcName: "main.main",
+		err:      errors.New("an error occurred"),
+		msg:      "something went wrong",
+	}
+
 	testCases := []struct {
 		name      string
 		formatter FormatFn
-		file      string
-		line      int
-		funcName  string
-		err       error
-		msg       string
 		expected  string
 	}{
 		{
@@ -37,21 +42,11 @@ func TestSetFormatter(t *testing.T) {
 			formatter: func(_ string, line int, funcName string, err error, msg string) string {
 				return funcName + "#" + strconv.Itoa(line) + " - " + msg + ": " + err.Error()
 			},
-			file:     "file.go",
-			line:     42,
-			funcName: "main.main",
-			err:      errors.New("an error occurred"),
-			msg:      "something went wrong",
 			expected: "main.main#42 - something went wrong: an error occurred",
 		},
 		{
 			name:      "DefaultFormatter",
 			formatter: defaultFormatter,
-			file:      "file.go",
-			line:      42,
-			funcName:  "main.main",
-			err:       errors.New("an error occurred"),
-			msg:       "something went wrong",
 			expected:  "main/file.go:42\tmain()\tsomething went wrong\nan error occurred",
 		},
 	}
@@ -60,8 +55,64 @@ func TestSetFormatter(t *testing.T) {
 		tt := testCase
 		t.Run(tt.name, func(t *testing.T) {
 			SetFormatter(tt.formatter)
-			result := _defaultFormatter(tt.file, tt.line, tt.funcName, tt.err, tt.msg)
-			require.Equal(t, tt.expected, result)
+			require.Equal(t, tt.expected, err.Error())
 		})
 	}
+
+	t.Run("when nil", func(t *testing.T) {
+		SetFormatter(func(string, int, string, error, string) string { return "custom" })
+		require.Equal(t, "custom", err.Error())
+
+		SetFormatter(nil)
+		require.Equal(t, defaultChainFormatter{}, _formatter)
+		require.Equal(t, "main/file.go:42\tmain()\tsomething went wrong\nan error occurred", err.Error())
+	})
+}
+
+func TestFormatter_Format(t *testing.T) {
+	t.Parallel()
+
+	err := Error{
+		file:     "main.go",
+		funcName: "main.main",
+		line:     42,
+		err: Error{
+			file:     "main.go",
+			funcName: "main.load",
+			line:     84,
+			err:      errors.New("original error"),
+			public:   "Order not found",
+		},
+		msg: "additional message",
+	}
+
+	t.Run("internal view", func(t *testing.T) {
+		t.Parallel()
+
+		exp := "main/main.go:42\tmain()\tadditional message\nmain/main.go:84\tload()\noriginal error"
+		require.Equal(t, exp, Formatter{}.Format(err))
+	})
+
+	t.Run("internal view with custom formatter", func(t *testing.T) {
+		t.Parallel()
+
+		fn := func(_ string, line int, funcName string, err error, _ string) string {
+			return funcName + "#" + strconv.Itoa(line) + ": " + err.Error()
+		}
+
+		exp := "main.main#42: main.load#84: original error"
+		require.Equal(t, exp, Formatter{Fn: fn}.Format(err))
+	})
+
+	t.Run("public view", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "Order not found", Formatter{View: ViewPublic}.Format(err))
+	})
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, Formatter{}.Format(nil))
+	})
 }
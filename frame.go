@@ -0,0 +1,78 @@
+package werr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Frame describes a single wrap site of an error chain.
+type Frame struct {
+	FuncName string  // FuncName is the fully qualified function name ("<pkg>.<name>").
+	File     string  // File is the file path of the wrap site.
+	Line     int     // Line is the line number of the wrap site.
+	PC       uintptr // PC is the program counter of the wrap site, as reported by runtime.Callers.
+	Message  string  // Message is the additional message with secret values redacted.
+	Public   string  // Public is the user-facing message, if any.
+	Args     []Field // Args are the named function arguments, if any.
+}
+
+// Frame returns the wrap site of this layer of the error.
+func (e Error) Frame() Frame {
+	return Frame{
+		FuncName: e.funcName,
+		File:     e.file,
+		Line:     e.line,
+		PC:       e.pc,
+		Message:  e.msg,
+		Public:   e.public,
+		Args:     e.Args(),
+	}
+}
+
+// AppendMessage appends the message of the wrap site followed by its named arguments,
+// rendered in the given redaction mode, as printed by the default formatter.
+func (f Frame) AppendMessage(dst []byte, mode Redaction) []byte {
+	return appendMessage(dst, f.Message, f.Args, mode)
+}
+
+// Package returns the import path of the package of the wrapping function,
+// e.g. "example.com/app/user" for "example.com/app/user.(*Store).Load".
+func (f Frame) Package() string {
+	pkg, _ := splitFuncName(f.FuncName)
+
+	return pkg
+}
+
+// Func returns the name of the wrapping function without its package,
+// e.g. "(*Store).Load" for "example.com/app/user.(*Store).Load".
+func (f Frame) Func() string {
+	_, fn := splitFuncName(f.FuncName)
+
+	return fn
+}
+
+// splitFuncName splits a fully qualified function name into the package path and the function.
+func splitFuncName(funcName string) (string, string) {
+	slash := strings.LastIndex(funcName, "/") + 1
+
+	if dot := strings.Index(funcName[slash:], "."); dot >= 0 {
+		return funcName[:slash+dot], funcName[slash+dot+1:]
+	}
+
+	return funcName, ""
+}
+
+// Frames returns the wrap sites of all werr layers of err, outermost first.
+// The chain is walked with errors.Unwrap, so layers below other wrapping errors,
+// e.g. fmt.Errorf with %w, are included.
+func Frames(err error) []Frame {
+	var frames []Frame
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(Error); ok { //nolint: errorlint
+			frames = append(frames, e.Frame())
+		}
+	}
+
+	return frames
+}
@@ -0,0 +1,52 @@
+package werr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrame_Package(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		funcName string
+		pkg      string
+		fn       string
+	}{
+		{funcName: "example.com/app/user.(*Store).Load", pkg: "example.com/app/user", fn: "(*Store).Load"},
+		{funcName: "example.com/app.v2/user.Load.func1", pkg: "example.com/app.v2/user", fn: "Load.func1"},
+		{funcName: "main.main", pkg: "main", fn: "main"},
+		{funcName: "main", pkg: "main"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.funcName, func(t *testing.T) {
+			t.Parallel()
+
+			f := Frame{FuncName: tc.funcName}
+			require.Equal(t, tc.pkg, f.Package())
+			require.Equal(t, tc.fn, f.Func())
+		})
+	}
+}
+
+func TestFrames(t *testing.T) {
+	t.Parallel()
+
+	inner := Wrap(errors.New("test"))
+	outer := Wrapf(fmt.Errorf("fmt: %w", inner), "outer")
+
+	frames := Frames(outer)
+	require.Len(t, frames, 2)
+	require.Equal(t, "outer", frames[0].Message)
+	require.Equal(t, "TestFrames", frames[0].Func())
+	require.Equal(t, "github.com/safeblock-dev/werr", frames[1].Package())
+
+	require.Empty(t, Frames(errors.New("test")))
+	require.Empty(t, Frames(nil))
+}
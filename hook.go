@@ -0,0 +1,82 @@
+package werr
+
+import "sync"
+
+// Hook is a function called with the frame of a new wrapped error and the error itself.
+type Hook func(Frame, error)
+
+var (
+	_wrapHooks  hooks //nolint: gochecknoglobals
+	_panicHooks hooks //nolint: gochecknoglobals
+)
+
+// hooks is a list of hooks, identified by pointer so that the same function can be registered twice.
+type hooks struct {
+	cowList[*Hook]
+}
+
+// OnWrap registers a hook called on every wrap, e.g. to count errors by site or sample them.
+// Hooks are called synchronously in registration order. A panic in a hook is recovered
+// and does not prevent the remaining hooks from running. Hooks must not wrap errors themselves.
+// The returned function unregisters the hook.
+func OnWrap(fn Hook) func() {
+	return _wrapHooks.add(fn)
+}
+
+// OnPanic registers a hook called on every panic converted by PanicToError.
+// Hooks follow the same rules as the ones registered with OnWrap.
+// The returned function unregisters the hook.
+func OnPanic(fn Hook) func() {
+	return _panicHooks.add(fn)
+}
+
+// add appends the hook to the list and returns a function that removes it.
+func (h *hooks) add(fn Hook) func() {
+	entry := &fn
+
+	h.update(func(list []*Hook) []*Hook {
+		return append(list, entry)
+	})
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() { h.remove(entry) })
+	}
+}
+
+// remove deletes the hook from the list.
+func (h *hooks) remove(entry *Hook) {
+	h.update(func(list []*Hook) []*Hook {
+		kept := list[:0]
+
+		for _, e := range list {
+			if e != entry {
+				kept = append(kept, e)
+			}
+		}
+
+		return kept
+	})
+}
+
+// call runs the registered hooks for the wrapped error.
+func (h *hooks) call(e Error) {
+	list := h.load()
+	if len(list) == 0 {
+		return
+	}
+
+	frame := e.Frame()
+
+	for _, fn := range list {
+		callHook(*fn, frame, e)
+	}
+}
+
+// callHook runs a single hook, recovering from its panic.
+func callHook(fn Hook, frame Frame, err error) {
+	defer func() { _ = recover() }()
+
+	fn(frame, err)
+}
@@ -0,0 +1,122 @@
+package werr_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// nolint: paralleltest
+func TestOnWrap(t *testing.T) {
+	originalErr := errors.New("original error")
+
+	t.Run("called on every wrap", func(t *testing.T) {
+		var frames []werr.Frame
+
+		remove := werr.OnWrap(func(frame werr.Frame, err error) {
+			require.ErrorIs(t, err, originalErr)
+
+			frames = append(frames, frame)
+		})
+		defer remove()
+
+		_ = werr.Wrap(originalErr)
+		_ = werr.Wrapf(originalErr, "id=%d", 42)
+		_, _ = werr.Wrapt(1, originalErr)
+		_ = werr.WrapArgs(originalErr, werr.Arg("id", 42))
+		_ = werr.WrapPublic(originalErr, "Not found")
+		_ = werr.Wrap(nil)
+
+		require.Len(t, frames, 5)
+
+		for _, frame := range frames {
+			require.Equal(t, "github.com/safeblock-dev/werr_test.TestOnWrap.func1", frame.FuncName)
+			require.NotZero(t, frame.Line)
+			require.NotZero(t, frame.PC)
+		}
+
+		require.Equal(t, "id=42", frames[1].Message)
+		require.Equal(t, []werr.Field{werr.Arg("id", 42)}, frames[3].Args)
+		require.Equal(t, "Not found", frames[4].Public)
+	})
+
+	t.Run("called in registration order", func(t *testing.T) {
+		var calls []int
+
+		defer werr.OnWrap(func(werr.Frame, error) { calls = append(calls, 1) })()
+		defer werr.OnWrap(func(werr.Frame, error) { calls = append(calls, 2) })()
+		defer werr.OnWrap(func(werr.Frame, error) { calls = append(calls, 3) })()
+
+		_ = werr.Wrap(originalErr)
+
+		require.Equal(t, []int{1, 2, 3}, calls)
+	})
+
+	t.Run("protected against panic", func(t *testing.T) {
+		var called bool
+
+		defer werr.OnWrap(func(werr.Frame, error) { panic("hook panic") })()
+		defer werr.OnWrap(func(werr.Frame, error) { called = true })()
+
+		require.NotPanics(t, func() { _ = werr.Wrap(originalErr) })
+		require.True(t, called)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		var calls int
+
+		remove := werr.OnWrap(func(werr.Frame, error) { calls++ })
+		_ = werr.Wrap(originalErr)
+
+		remove()
+		remove()
+		_ = werr.Wrap(originalErr)
+
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("concurrent registration", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				remove := werr.OnWrap(func(werr.Frame, error) {})
+				_ = werr.Wrap(originalErr)
+
+				remove()
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+// nolint: paralleltest
+func TestOnPanic(t *testing.T) {
+	var (
+		frames []werr.Frame
+		wraps  int
+	)
+
+	defer werr.OnPanic(func(frame werr.Frame, err error) {
+		require.Error(t, err)
+
+		frames = append(frames, frame)
+	})()
+	defer werr.OnWrap(func(werr.Frame, error) { wraps++ })()
+
+	require.NoError(t, werr.PanicToError(nil))
+	require.Error(t, werr.PanicToError("boom"))
+
+	require.Len(t, frames, 1)
+	require.Equal(t, "github.com/safeblock-dev/werr_test.TestOnPanic", frames[0].FuncName)
+	require.Zero(t, wraps)
+}
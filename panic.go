@@ -4,21 +4,43 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strings"
 )
 
+// panicFormat is the message of the errors created by PanicToError, followed by the stack of the panic.
+const panicFormat = "panic recovered"
+
 // PanicToError converts a recovered panic to an error.
 func PanicToError(p any) error {
-	msg := "panic recovered\n"
+	msg := panicFormat + "\n"
 	msg += string(debug.Stack())
 
+	var e Error
+
 	switch v := p.(type) {
 	case nil:
 		return nil
 	case error:
-		return newError(v, msg)
+		e = newError(v, msg)
 	case string:
-		return newError(errors.New(v), msg)
+		e = newError(errors.New(v), msg)
 	default:
-		return newError(fmt.Errorf("%#v", v), msg)
+		e = newError(fmt.Errorf("%#v", v), msg)
+	}
+
+	// The stack differs between goroutines, keep it out of fingerprints.
+	e.format = panicFormat
+	_panicHooks.call(e)
+
+	return e
+}
+
+// shortPanic returns the message of a layer created by PanicToError without the stack of the panic,
+// or the message unchanged if it is not one.
+func shortPanic(msg string) string {
+	if strings.HasPrefix(msg, panicFormat+"\n") {
+		return panicFormat
 	}
+
+	return msg
 }
@@ -0,0 +1,266 @@
+package werr
+
+import (
+	"path"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// URL templates of permalinks for common forges. The placeholders are replaced by
+// the repository URL, the revision, the path of the file in the repository and the line.
+const (
+	GitHubTemplate = "{repo}/blob/{revision}/{path}#L{line}"
+	GitLabTemplate = "{repo}/-/blob/{revision}/{path}#L{line}"
+	GiteaTemplate  = "{repo}/src/commit/{revision}/{path}#L{line}"
+)
+
+// Repository describes where the sources of a module are hosted.
+type Repository struct {
+	URL      string // URL is the address of the repository, e.g. "https://github.com/acme/app".
+	Dir      string // Dir is the directory of the module in the repository, empty for the root.
+	Revision string // Revision is the commit or tag of the sources; Permalinks.Revision is used if empty.
+	Template string // Template is the URL template, GitHubTemplate if empty.
+}
+
+// Permalinks turns wrap sites into links to their source lines at the revision of the build,
+// so that traces can be read without the sources checked out.
+type Permalinks struct {
+	Revision string                // Revision is the commit of the build, e.g. the vcs.revision build setting.
+	Modules  map[string]Repository // Modules maps module paths to the repositories hosting them.
+}
+
+// BuildPermalinks returns permalinks configured from the build information of the binary:
+// the main module and the dependencies hosted on github.com or gitlab.com are mapped
+// to their repositories, at the vcs.revision of the build and at the versions of the dependencies.
+// Other hosts, e.g. Gitea instances, are added to Modules by hand:
+//
+//	p := werr.BuildPermalinks()
+//	p.Modules["git.example.com/acme/app"] = werr.Repository{URL: "https://git.example.com/acme/app", Template: werr.GiteaTemplate}
+//	werr.SetPermalinks(p)
+//
+// Links of the main package are only available in binaries built with -trimpath,
+// which records file paths relative to the module path.
+func BuildPermalinks() Permalinks {
+	p := Permalinks{Modules: make(map[string]Repository)}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return p
+	}
+
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			p.Revision = s.Value
+		}
+	}
+
+	if repo, ok := hostedRepository(info.Main.Path); ok {
+		p.Modules[info.Main.Path] = repo
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			continue
+		}
+
+		if repo, ok := hostedRepository(dep.Path); ok {
+			repo.Revision = versionRevision(dep.Version, repo.Dir)
+			p.Modules[dep.Path] = repo
+		}
+	}
+
+	return p
+}
+
+// hostedRepository returns the repository of a module hosted on a known forge.
+func hostedRepository(module string) (Repository, bool) {
+	parts := strings.Split(module, "/")
+	if len(parts) < 3 { //nolint: mnd
+		return Repository{}, false
+	}
+
+	var template string
+
+	switch parts[0] {
+	case "github.com":
+		template = GitHubTemplate
+	case "gitlab.com":
+		template = GitLabTemplate
+	default:
+		return Repository{}, false
+	}
+
+	// A major version suffix is not a directory of the repository.
+	dir := parts[3:]
+	if n := len(dir); n > 0 && isMajorVersion(dir[n-1]) {
+		dir = dir[:n-1]
+	}
+
+	return Repository{
+		URL:      "https://" + strings.Join(parts[:3], "/"),
+		Dir:      strings.Join(dir, "/"),
+		Template: template,
+	}, true
+}
+
+// isMajorVersion reports whether a path element is a major version suffix, e.g. "v2".
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' { //nolint: mnd
+		return false
+	}
+
+	_, err := strconv.Atoi(s[1:])
+
+	return err == nil
+}
+
+// versionRevision returns the revision of a module version: the commit of a pseudo-version,
+// e.g. "v0.0.0-20240102030405-abcdef123456", or the tag otherwise. The tags of a module
+// in a subdirectory of its repository are prefixed with the directory, e.g. "service/s3/v1.50.0".
+func versionRevision(version, dir string) string {
+	version = strings.TrimSuffix(version, "+incompatible")
+
+	if i := strings.LastIndex(version, "-"); i >= 0 && len(version)-i-1 == 12 && strings.Count(version, "-") >= 2 { //nolint: mnd
+		return version[i+1:]
+	}
+
+	if dir != "" {
+		return dir + "/" + version
+	}
+
+	return version
+}
+
+// Link returns the permalink of a wrap site, or an empty string if its module is not mapped
+// to a repository or its revision is unknown.
+func (p Permalinks) Link(f Frame) string {
+	module, rel, ok := p.module(f)
+	if !ok {
+		return ""
+	}
+
+	repo := p.Modules[module]
+
+	revision := repo.Revision
+	if revision == "" {
+		revision = p.Revision
+	}
+
+	if revision == "" || repo.URL == "" {
+		return ""
+	}
+
+	template := repo.Template
+	if template == "" {
+		template = GitHubTemplate
+	}
+
+	return strings.NewReplacer(
+		"{repo}", strings.TrimSuffix(repo.URL, "/"),
+		"{revision}", revision,
+		"{path}", path.Join(repo.Dir, rel),
+		"{line}", strconv.Itoa(f.Line),
+	).Replace(template)
+}
+
+// module returns the mapped module of a wrap site and the path of its file in the module.
+// Files recorded relative to the module path (-trimpath) are matched directly, other files
+// by the package of the function.
+func (p Permalinks) module(f Frame) (string, string, bool) {
+	if module, rest, ok := p.longestModule(f.File); ok {
+		return module, rest, true
+	}
+
+	// External test packages are in the directory of the package they test.
+	module, rest, ok := p.longestModule(strings.TrimSuffix(f.Package(), "_test"))
+	if !ok {
+		return "", "", false
+	}
+
+	return module, path.Join(rest, path.Base(f.File)), true
+}
+
+// longestModule returns the longest mapped module path prefixing name and the rest of name.
+func (p Permalinks) longestModule(name string) (string, string, bool) {
+	var module string
+
+	for m := range p.Modules {
+		if len(m) > len(module) && (name == m || strings.HasPrefix(name, m+"/")) {
+			module = m
+		}
+	}
+
+	if module == "" {
+		return "", "", false
+	}
+
+	return module, strings.TrimPrefix(strings.TrimPrefix(name, module), "/"), true
+}
+
+var _permalinks atomic.Pointer[Permalinks] //nolint: gochecknoglobals
+
+// SetPermalinks enables the permalinks of Frame.Permalink, used by the template formatter,
+// the Recorder sites and the werrsentry and werrotel outputs. Permalinks are disabled until
+// it is called, typically with BuildPermalinks:
+//
+//	werr.SetPermalinks(werr.BuildPermalinks())
+func SetPermalinks(p Permalinks) {
+	_permalinks.Store(&p)
+}
+
+// permalinks returns the permalinks set with SetPermalinks, or no permalinks.
+func permalinks() *Permalinks {
+	if p := _permalinks.Load(); p != nil {
+		return p
+	}
+
+	return &Permalinks{}
+}
+
+// Permalink returns the link to the source line of the wrap site, see SetPermalinks,
+// or an empty string if it is not available.
+func (f Frame) Permalink() string {
+	return permalinks().Link(f)
+}
+
+// PermalinkFormatter returns a chain formatter rendering the layout of the default formatter
+// with the location of every wrap site replaced by its permalink, when it is available:
+//
+//	https://github.com/acme/app/blob/4f2a9c1/config/load.go#L84	load()	reading file
+//	open config.yaml: no such file or directory
+func PermalinkFormatter(p Permalinks) ChainFormatter {
+	return permalinkFormatter{p: p}
+}
+
+// permalinkFormatter renders traces with permalinks.
+type permalinkFormatter struct {
+	p Permalinks
+}
+
+func (f permalinkFormatter) AppendChain(dst []byte, c Chain) []byte {
+	for _, fr := range c.Frames {
+		link := f.p.Link(fr)
+		if link == "" {
+			dst = appendLine(dst, fr.File, fr.Line, fr.FuncName, fr.Message, fr.Args, c.Redaction)
+
+			continue
+		}
+
+		_, fn := location(fr.File, fr.Line, fr.FuncName)
+		dst = append(append(append(dst, link...), '\t'), fn...)
+
+		if fr.Message != "" || len(fr.Args) > 0 {
+			dst = appendMessage(append(dst, '\t'), fr.Message, fr.Args, c.Redaction)
+		}
+
+		dst = append(dst, '\n')
+	}
+
+	if c.Cause != nil {
+		dst = append(dst, c.Cause.Error()...)
+	}
+
+	return dst
+}
@@ -0,0 +1,169 @@
+package werr
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermalinks_Link(t *testing.T) {
+	t.Parallel()
+
+	p := Permalinks{
+		Revision: "4f2a9c1",
+		Modules: map[string]Repository{
+			"github.com/acme/app":       {URL: "https://github.com/acme/app/"},
+			"github.com/acme/app/tools": {URL: "https://github.com/acme/app", Dir: "tools", Revision: "tools/v1.2.0"},
+			"gitlab.com/acme/lib":       {URL: "https://gitlab.com/acme/lib", Template: GitLabTemplate},
+			"git.example.com/acme/svc":  {URL: "https://git.example.com/acme/svc", Template: GiteaTemplate},
+			"example.com/norepo":        {},
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		frame Frame
+		want  string
+	}{
+		{
+			name:  "by package",
+			frame: Frame{FuncName: "github.com/acme/app/config.(*Loader).Load", File: "/home/dev/app/config/load.go", Line: 84},
+			want:  "https://github.com/acme/app/blob/4f2a9c1/config/load.go#L84",
+		},
+		{
+			name:  "by trimmed path",
+			frame: Frame{FuncName: "main.main", File: "github.com/acme/app/cmd/app/main.go", Line: 12},
+			want:  "https://github.com/acme/app/blob/4f2a9c1/cmd/app/main.go#L12",
+		},
+		{
+			name:  "with nested module",
+			frame: Frame{FuncName: "github.com/acme/app/tools/gen.Run", File: "/src/tools/gen/run.go", Line: 7},
+			want:  "https://github.com/acme/app/blob/tools/v1.2.0/tools/gen/run.go#L7",
+		},
+		{
+			name:  "with gitlab",
+			frame: Frame{FuncName: "gitlab.com/acme/lib.Do", File: "/go/pkg/mod/gitlab.com/acme/lib@v1.0.0/do.go", Line: 3},
+			want:  "https://gitlab.com/acme/lib/-/blob/4f2a9c1/do.go#L3",
+		},
+		{
+			name:  "with gitea",
+			frame: Frame{FuncName: "git.example.com/acme/svc/api.Serve", File: "/src/api/serve.go", Line: 99},
+			want:  "https://git.example.com/acme/svc/src/commit/4f2a9c1/api/serve.go#L99",
+		},
+		{
+			name:  "when main package without trimpath",
+			frame: Frame{FuncName: "main.main", File: "/home/dev/app/main.go", Line: 1},
+		},
+		{
+			name:  "when module not mapped",
+			frame: Frame{FuncName: "github.com/other/pkg.Do", File: "/src/do.go", Line: 1},
+		},
+		{
+			name:  "when prefix is not a module",
+			frame: Frame{FuncName: "github.com/acme/application.Do", File: "/src/do.go", Line: 1},
+		},
+		{
+			name:  "when repository unknown",
+			frame: Frame{FuncName: "example.com/norepo.Do", File: "/src/do.go", Line: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tc.want, p.Link(tc.frame))
+		})
+	}
+
+	t.Run("when revision unknown", func(t *testing.T) {
+		t.Parallel()
+
+		p := Permalinks{Modules: map[string]Repository{"github.com/acme/app": {URL: "https://github.com/acme/app"}}}
+		require.Empty(t, p.Link(Frame{FuncName: "github.com/acme/app.Do", File: "do.go", Line: 1}))
+	})
+}
+
+func TestHostedRepository(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		module string
+		want   Repository
+		ok     bool
+	}{
+		{module: "github.com/acme/app", want: Repository{URL: "https://github.com/acme/app", Template: GitHubTemplate}, ok: true},
+		{module: "github.com/acme/app/v2", want: Repository{URL: "https://github.com/acme/app", Template: GitHubTemplate}, ok: true},
+		{module: "github.com/acme/app/tools/v3", want: Repository{URL: "https://github.com/acme/app", Dir: "tools", Template: GitHubTemplate}, ok: true},
+		{module: "gitlab.com/acme/lib", want: Repository{URL: "https://gitlab.com/acme/lib", Template: GitLabTemplate}, ok: true},
+		{module: "golang.org/x/tools"},
+		{module: "github.com/acme"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.module, func(t *testing.T) {
+			t.Parallel()
+
+			repo, ok := hostedRepository(tc.module)
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.want, repo)
+		})
+	}
+}
+
+func TestVersionRevision(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "v1.2.3", versionRevision("v1.2.3", ""))
+	require.Equal(t, "v2.0.0", versionRevision("v2.0.0+incompatible", ""))
+	require.Equal(t, "v1.0.0-rc.1", versionRevision("v1.0.0-rc.1", ""))
+	require.Equal(t, "abcdef123456", versionRevision("v0.0.0-20240102030405-abcdef123456", ""))
+	require.Equal(t, "abcdef123456", versionRevision("v1.2.4-0.20240102030405-abcdef123456", ""))
+
+	t.Run("with nested module", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "service/s3/v1.50.0", versionRevision("v1.50.0", "service/s3"))
+		require.Equal(t, "abcdef123456", versionRevision("v0.0.0-20240102030405-abcdef123456", "service/s3"))
+	})
+}
+
+func permalinkWrap(err error) error {
+	return Wrapf(err, "link")
+}
+
+// nolint: paralleltest
+func TestSetPermalinks(t *testing.T) {
+	defer SetPermalinks(Permalinks{})
+
+	t.Run("when not set", func(t *testing.T) {
+		var e Error
+		require.ErrorAs(t, permalinkWrap(errors.New("test")), &e)
+		require.Empty(t, permalinks().Modules)
+		require.Empty(t, e.Frame().Permalink())
+	})
+
+	SetPermalinks(Permalinks{
+		Revision: "main",
+		Modules:  map[string]Repository{"github.com/safeblock-dev/werr": {URL: "https://github.com/safeblock-dev/werr"}},
+	})
+
+	var e Error
+	require.ErrorAs(t, permalinkWrap(errors.New("test")), &e)
+
+	link := "https://github.com/safeblock-dev/werr/blob/main/permalink_test.go#L" + strconv.Itoa(e.Line())
+	require.Equal(t, link, e.Frame().Permalink())
+
+	fn, err := TemplateFormatter("{{range .Frames}}{{.URL}}{{end}}")
+	require.NoError(t, err)
+	require.Equal(t, link, Formatter{Fn: fn}.Format(e))
+
+	out := Formatter{Chain: PermalinkFormatter(*permalinks())}.Format(Wrap(e))
+	require.Contains(t, out, "\tTestSetPermalinks()\n"+link+"\tpermalinkWrap()\tlink\ntest")
+}
@@ -0,0 +1,248 @@
+package werr
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// PresetOption configures a preset formatter.
+type PresetOption func(*preset)
+
+// WithMaxFrames limits the number of wrap sites rendered by a preset formatter.
+// The wrap sites closest to the start of the output are kept and the others are
+// replaced by a "... N more" line, or by "..." on a single line. Zero means no limit.
+func WithMaxFrames(n int) PresetOption {
+	return func(p *preset) {
+		p.maxFrames = n
+	}
+}
+
+// WithIndent sets the prefix of the lines of wrap sites in multi-line preset formatters.
+func WithIndent(indent string) PresetOption {
+	return func(p *preset) {
+		p.indent = indent
+	}
+}
+
+// SingleLineFormatter returns a formatter rendering the messages of the chain and the root cause
+// on a single line, outermost first, like errors wrapped with fmt.Errorf:
+//
+//	loading config: reading file: open config.yaml: no such file or directory
+//
+// Wrap sites without a message are omitted.
+func SingleLineFormatter(opts ...PresetOption) FormatFn {
+	return newPreset(singleLine, "", opts)
+}
+
+// CompactFormatter returns a formatter rendering every wrap site with its location
+// on a single line, outermost first, separated by " | ":
+//
+//	main/main.go:42 main(): loading config | main/load.go:84 load() | open config.yaml: no such file or directory
+func CompactFormatter(opts ...PresetOption) FormatFn {
+	return newPreset(compact, "", opts)
+}
+
+// JavaFormatter returns a formatter rendering the chain like a Java stack trace: every
+// wrap site with a message starts a "Caused by:" block listing its location and the
+// locations of the following wrap sites without a message, and the root cause ends the trace:
+//
+//	loading config
+//		at main.main(main.go:42)
+//	Caused by: reading file
+//		at main.load(load.go:84)
+//		at main.read(load.go:97)
+//	Caused by: open config.yaml: no such file or directory
+//
+// The first block is headed by the location of the outermost wrap site if it has no message.
+// The lines of wrap sites are indented with a tab unless WithIndent is given.
+func JavaFormatter(opts ...PresetOption) FormatFn {
+	return newPreset(java, "\t", opts)
+}
+
+// RootCauseFirstFormatter returns a formatter rendering the root cause first, followed
+// by the wrap sites in the layout of the default formatter, innermost first:
+//
+//	open config.yaml: no such file or directory
+//	main/load.go:84	load()	reading file
+//	main/main.go:42	main()	loading config
+func RootCauseFirstFormatter(opts ...PresetOption) FormatFn {
+	return newPreset(rootCauseFirst, "", opts)
+}
+
+// preset is a formatter rendering the whole chain at the outermost layer.
+type preset struct {
+	maxFrames int
+	indent    string
+	render    func(p preset, layers []layer, cause string) string
+}
+
+func newPreset(render func(preset, []layer, string) string, indent string, opts []PresetOption) FormatFn {
+	p := preset{indent: indent, render: render}
+
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	return p.format
+}
+
+func (p preset) format(file string, line int, funcName string, err error, msg string) string {
+	layers, cause := chainLayers(file, line, funcName, err, msg)
+
+	var mode Redaction
+	if len(layers) > 0 {
+		mode = layers[0].mode
+	}
+
+	layers, cause = foreignLayers(layers, cause, mode)
+
+	// The stack of a recovered panic would break the layout of the presets.
+	for i := range layers {
+		layers[i].msg = shortPanic(layers[i].msg)
+	}
+
+	var text string
+	if cause != nil {
+		text = cause.Error()
+	}
+
+	return p.render(p, layers, text)
+}
+
+// limit returns the first layers allowed by the frame limit and the number of omitted layers.
+func (p preset) limit(layers []layer) ([]layer, int) {
+	if p.maxFrames <= 0 || len(layers) <= p.maxFrames {
+		return layers, 0
+	}
+
+	return layers[:p.maxFrames], len(layers) - p.maxFrames
+}
+
+// more returns the line replacing the omitted layers.
+func (p preset) more(n int) string {
+	return p.indent + "... " + strconv.Itoa(n) + " more\n"
+}
+
+func singleLine(p preset, layers []layer, cause string) string {
+	layers, omitted := p.limit(layers)
+
+	var b strings.Builder
+
+	for _, l := range layers {
+		if l.msg != "" {
+			b.WriteString(l.msg + ": ")
+		}
+	}
+
+	if omitted > 0 {
+		b.WriteString("...: ")
+	}
+
+	return b.String() + cause
+}
+
+func compact(p preset, layers []layer, cause string) string {
+	layers, omitted := p.limit(layers)
+
+	var b strings.Builder
+
+	for _, l := range layers {
+		switch {
+		case l.foreign && l.msg == "":
+			continue
+		case l.foreign:
+			b.WriteString(l.msg)
+		default:
+			source, fn := location(l.file, l.line, l.funcName)
+			b.WriteString(source + " " + fn)
+
+			if l.msg != "" {
+				b.WriteString(": " + l.msg)
+			}
+		}
+
+		b.WriteString(" | ")
+	}
+
+	if omitted > 0 {
+		b.WriteString("... | ")
+	}
+
+	return b.String() + cause
+}
+
+func java(p preset, layers []layer, cause string) string {
+	layers, omitted := p.limit(layers)
+
+	var b strings.Builder
+
+	for i, l := range layers {
+		if l.foreign {
+			switch {
+			case l.msg != "" && i > 0:
+				b.WriteString("Caused by: " + l.msg + "\n")
+			case l.msg != "":
+				b.WriteString(l.msg + "\n")
+			}
+
+			continue
+		}
+
+		site := l.funcName + "(" + path.Base(l.file) + ":" + strconv.Itoa(l.line) + ")"
+
+		switch {
+		case l.msg != "" && i > 0:
+			b.WriteString("Caused by: " + l.msg + "\n")
+		case l.msg != "":
+			b.WriteString(l.msg + "\n")
+		case i == 0:
+			b.WriteString(site + "\n")
+		}
+
+		b.WriteString(p.indent + "at " + site + "\n")
+	}
+
+	if omitted > 0 {
+		b.WriteString(p.more(omitted))
+	}
+
+	return b.String() + "Caused by: " + cause
+}
+
+func rootCauseFirst(p preset, layers []layer, cause string) string {
+	// The innermost layers are rendered first, so the frame limit keeps them.
+	reversed := make([]layer, len(layers))
+	for i, l := range layers {
+		reversed[len(layers)-1-i] = l
+	}
+
+	layers, omitted := p.limit(reversed)
+
+	var b strings.Builder
+
+	b.WriteString(cause)
+
+	for _, l := range layers {
+		if l.foreign {
+			if l.msg != "" {
+				b.WriteString("\n" + p.indent + l.msg)
+			}
+
+			continue
+		}
+
+		source, fn := location(l.file, l.line, l.funcName)
+		b.WriteString("\n" + p.indent + source + "\t" + fn)
+
+		if l.msg != "" {
+			b.WriteString("\t" + l.msg)
+		}
+	}
+
+	if omitted > 0 {
+		b.WriteString("\n" + strings.TrimSuffix(p.more(omitted), "\n"))
+	}
+
+	return b.String()
+}
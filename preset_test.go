@@ -0,0 +1,208 @@
+package werr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresetFormatters(t *testing.T) {
+	t.Parallel()
+
+	err := Error{
+		file:     "/src/main.go",
+		funcName: "main.main",
+		line:     42,
+		msg:      "loading config",
+		err: Error{
+			file:     "/src/load.go",
+			funcName: "main.load",
+			line:     84,
+			msg:      "reading file",
+			err: Error{
+				file:     "/src/load.go",
+				funcName: "main.read",
+				line:     97,
+				err:      errors.New("no such file"),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name string
+		fn   FormatFn
+		exp  string
+	}{
+		{
+			name: "single line",
+			fn:   SingleLineFormatter(),
+			exp:  "loading config: reading file: no such file",
+		},
+		{
+			name: "single line with max frames",
+			fn:   SingleLineFormatter(WithMaxFrames(1)),
+			exp:  "loading config: ...: no such file",
+		},
+		{
+			name: "compact",
+			fn:   CompactFormatter(),
+			exp:  "main/main.go:42 main(): loading config | main/load.go:84 load(): reading file | main/load.go:97 read() | no such file",
+		},
+		{
+			name: "compact with max frames",
+			fn:   CompactFormatter(WithMaxFrames(2)),
+			exp:  "main/main.go:42 main(): loading config | main/load.go:84 load(): reading file | ... | no such file",
+		},
+		{
+			name: "java",
+			fn:   JavaFormatter(),
+			exp: "loading config\n" +
+				"\tat main.main(main.go:42)\n" +
+				"Caused by: reading file\n" +
+				"\tat main.load(load.go:84)\n" +
+				"\tat main.read(load.go:97)\n" +
+				"Caused by: no such file",
+		},
+		{
+			name: "java with options",
+			fn:   JavaFormatter(WithMaxFrames(2), WithIndent("    ")),
+			exp: "loading config\n" +
+				"    at main.main(main.go:42)\n" +
+				"Caused by: reading file\n" +
+				"    at main.load(load.go:84)\n" +
+				"    ... 1 more\n" +
+				"Caused by: no such file",
+		},
+		{
+			name: "root cause first",
+			fn:   RootCauseFirstFormatter(),
+			exp: "no such file\n" +
+				"main/load.go:97\tread()\n" +
+				"main/load.go:84\tload()\treading file\n" +
+				"main/main.go:42\tmain()\tloading config",
+		},
+		{
+			name: "root cause first with options",
+			fn:   RootCauseFirstFormatter(WithMaxFrames(1), WithIndent("  ")),
+			exp: "no such file\n" +
+				"  main/load.go:97\tread()\n" +
+				"  ... 2 more",
+		},
+	}
+
+	for _, testCase := range testCases {
+		tt := testCase
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.exp, err.Format(tt.fn))
+		})
+	}
+
+	t.Run("java without message", func(t *testing.T) {
+		t.Parallel()
+
+		inner, ok := err.err.(Error).err.(Error) //nolint: errorlint
+		require.True(t, ok)
+
+		exp := "main.read(load.go:97)\n" +
+			"\tat main.read(load.go:97)\n" +
+			"Caused by: no such file"
+		require.Equal(t, exp, inner.Format(JavaFormatter()))
+	})
+
+	t.Run("with formatter", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "loading config: reading file: no such file", Formatter{Fn: SingleLineFormatter()}.Format(err))
+	})
+
+	t.Run("with wrapping error", func(t *testing.T) {
+		t.Parallel()
+
+		inner, ok := err.err.(Error) //nolint: errorlint
+		require.True(t, ok)
+
+		mixed := Error{
+			file:     "/src/main.go",
+			funcName: "main.main",
+			line:     42,
+			msg:      "loading config",
+			err:      fmt.Errorf("retrying: %w", inner),
+		}
+
+		testCases := []struct {
+			name string
+			fn   FormatFn
+			exp  string
+		}{
+			{
+				name: "single line",
+				fn:   SingleLineFormatter(),
+				exp:  "loading config: retrying: reading file: no such file",
+			},
+			{
+				name: "compact",
+				fn:   CompactFormatter(),
+				exp:  "main/main.go:42 main(): loading config | retrying | main/load.go:84 load(): reading file | main/load.go:97 read() | no such file",
+			},
+			{
+				name: "java",
+				fn:   JavaFormatter(),
+				exp: "loading config\n" +
+					"\tat main.main(main.go:42)\n" +
+					"Caused by: retrying\n" +
+					"Caused by: reading file\n" +
+					"\tat main.load(load.go:84)\n" +
+					"\tat main.read(load.go:97)\n" +
+					"Caused by: no such file",
+			},
+			{
+				name: "root cause first",
+				fn:   RootCauseFirstFormatter(),
+				exp: "no such file\n" +
+					"main/load.go:97\tread()\n" +
+					"main/load.go:84\tload()\treading file\n" +
+					"retrying\n" +
+					"main/main.go:42\tmain()\tloading config",
+			},
+		}
+
+		for _, testCase := range testCases {
+			tt := testCase
+
+			require.Equal(t, tt.exp, mixed.Format(tt.fn), tt.name)
+			require.Equal(t, tt.exp, Formatter{Fn: tt.fn}.Format(mixed), tt.name)
+		}
+
+		wrapped := fmt.Errorf("starting: %w", mixed)
+		require.Equal(t, "starting: loading config: retrying: reading file: no such file",
+			Formatter{Fn: SingleLineFormatter()}.Format(wrapped))
+	})
+
+	t.Run("with recovered panic", func(t *testing.T) {
+		t.Parallel()
+
+		panicErr, ok := PanicToError("boom").(Error) //nolint: errorlint
+		require.True(t, ok)
+
+		wrapped := Error{
+			file:     "/src/main.go",
+			funcName: "main.main",
+			line:     42,
+			msg:      "serving",
+			err:      panicErr,
+		}
+
+		require.Equal(t, "serving: panic recovered: boom", wrapped.Format(SingleLineFormatter()))
+		require.Equal(t, "serving: panic recovered: boom", Formatter{Fn: SingleLineFormatter()}.Format(wrapped))
+
+		java := wrapped.Format(JavaFormatter())
+		require.True(t, strings.HasPrefix(java, "serving\n\tat main.main(main.go:42)\nCaused by: panic recovered\n"), java)
+		require.NotContains(t, java, "goroutine")
+	})
+}
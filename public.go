@@ -0,0 +1,41 @@
+package werr
+
+import (
+	"errors"
+)
+
+// DefaultPublicMessage is returned by PublicMessage when no layer of the error carries a public message.
+const DefaultPublicMessage = "internal error"
+
+// WrapPublic takes an error and a user-facing message and returns a new wrapped error.
+// If the input error (err) is nil, the function returns nil.
+// The public message is kept apart from the internal message text: it is not part of
+// Error() output and can be retrieved with PublicMessage.
+func WrapPublic(err error, public string) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, "")
+	e.public = public
+	_wrapHooks.call(e)
+
+	return e
+}
+
+// PublicMessage returns the outermost public message found in the error chain.
+// If no layer carries a public message, DefaultPublicMessage is returned.
+// If the error is nil, the function returns an empty string.
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(Error); ok && e.public != "" { //nolint: errorlint
+			return e.public
+		}
+	}
+
+	return DefaultPublicMessage
+}
@@ -0,0 +1,75 @@
+package werr_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+func TestWrapPublic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		originalErr := errors.New("sql: no rows in result set")
+		wrappedErr := werr.WrapPublic(originalErr, "Order not found")
+
+		require.IsType(t, werr.Error{}, wrappedErr)
+		require.ErrorIs(t, wrappedErr, originalErr)
+		require.Equal(t, "Order not found", wrappedErr.(werr.Error).Public())
+		require.NotContains(t, wrappedErr.Error(), "Order not found")
+	})
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, werr.WrapPublic(nil, "Order not found"))
+	})
+}
+
+func TestPublicMessage(t *testing.T) {
+	t.Parallel()
+
+	originalErr := errors.New("sql: no rows in result set")
+
+	t.Run("outermost public message", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.WrapPublic(originalErr, "Order not found")
+		err = werr.Wrapf(err, "load order id=%d", 42)
+		err = fmt.Errorf("handler: %w", err)
+		err = werr.WrapPublic(err, "Checkout failed")
+		err = werr.Wrap(err)
+
+		require.Equal(t, "Checkout failed", werr.PublicMessage(err))
+	})
+
+	t.Run("inner public message", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.WrapPublic(originalErr, "Order not found")
+		err = fmt.Errorf("handler: %w", werr.Wrap(err))
+
+		require.Equal(t, "Order not found", werr.PublicMessage(err))
+	})
+
+	t.Run("without public message", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, "load order id=%d", 42)
+
+		require.Equal(t, werr.DefaultPublicMessage, werr.PublicMessage(err))
+		require.Equal(t, werr.DefaultPublicMessage, werr.PublicMessage(originalErr))
+	})
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Empty(t, werr.PublicMessage(nil))
+	})
+}
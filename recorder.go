@@ -0,0 +1,216 @@
+package werr
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrorGroup is a group of occurrences of the same error in a Recorder, see Fingerprint.
+type ErrorGroup struct {
+	Fingerprint string      `json:"fingerprint"` // Fingerprint identifies the group.
+	Count       int         `json:"count"`       // Count is the number of occurrences.
+	FirstSeen   time.Time   `json:"first_seen"`  // FirstSeen is the time of the first occurrence.
+	LastSeen    time.Time   `json:"last_seen"`   // LastSeen is the time of the last occurrence.
+	Message     string      `json:"message"`     // Message is the last occurrence on a single line, see SingleLineFormatter.
+	Trace       string      `json:"trace"`       // Trace is the last occurrence in the layout of the default formatter.
+	Sites       []ErrorSite `json:"sites"`       // Sites are the wrap sites of the last occurrence, outermost first.
+}
+
+// ErrorSite is a wrap site of an error recorded by a Recorder.
+type ErrorSite struct {
+	FuncName string            `json:"func"`              // FuncName is the fully qualified function name.
+	File     string            `json:"file"`              // File is the file path of the wrap site.
+	Line     int               `json:"line"`              // Line is the line number of the wrap site.
+	Message  string            `json:"message,omitempty"` // Message is the additional message of the wrap site, if any.
+	Args     map[string]string `json:"args,omitempty"`    // Args are the named arguments of the wrap site, if any.
+	URL      string            `json:"url,omitempty"`     // URL is the permalink of the wrap site, if available, see SetPermalinks.
+}
+
+// Recorder keeps the most recently seen distinct errors of a process, grouped by Fingerprint,
+// e.g. to inspect them during an incident. When it is full, the least recently seen group is dropped.
+// It is safe for concurrent use. Secret values are redacted.
+//
+// A Recorder is fed by explicit calls to Record, or by hooks:
+//
+//	rec := werr.NewRecorder(100)
+//	werr.OnPanic(rec.Hook)
+//	http.Handle("/debug/errors", rec)
+//
+// Wrap hooks see every layer of a chain, so with werr.OnWrap(rec.Hook) each layer
+// is recorded in its own group; Record at request boundaries avoids that.
+type Recorder struct {
+	mu     sync.Mutex
+	size   int
+	groups []*ErrorGroup // groups are ordered by last occurrence, most recent last.
+	index  map[string]*ErrorGroup
+	now    func() time.Time
+}
+
+// NewRecorder returns a recorder keeping at most size groups.
+// A size less than one keeps a single group.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+
+	return &Recorder{
+		size:  size,
+		index: make(map[string]*ErrorGroup, size),
+		now:   time.Now,
+	}
+}
+
+// Record adds an occurrence of err. Nil errors are ignored.
+func (r *Recorder) Record(err error) {
+	if err == nil {
+		return
+	}
+
+	fp := Fingerprint(err)
+	message := Formatter{Fn: SingleLineFormatter()}.Format(err)
+	// The trace is rendered in the default layout: the global formatter may add
+	// terminal escape codes, which do not belong in the page and the JSON.
+	trace := Formatter{Chain: defaultChainFormatter{}}.Format(err)
+
+	frames := Frames(err)
+	sites := make([]ErrorSite, len(frames))
+
+	for i, f := range frames {
+		sites[i] = ErrorSite{FuncName: f.FuncName, File: f.File, Line: f.Line, Message: f.Message, URL: f.Permalink()}
+
+		if len(f.Args) > 0 {
+			sites[i].Args = make(map[string]string, len(f.Args))
+			for _, field := range f.Args {
+				sites[i].Args[field.Key] = string(field.AppendValue(nil, Redacted))
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+
+	g, ok := r.index[fp]
+	if ok {
+		r.remove(g)
+	} else {
+		if len(r.groups) == r.size {
+			delete(r.index, r.groups[0].Fingerprint)
+			r.remove(r.groups[0])
+		}
+
+		g = &ErrorGroup{Fingerprint: fp, FirstSeen: now}
+		r.index[fp] = g
+	}
+
+	g.Count++
+	g.LastSeen = now
+	g.Message = message
+	g.Trace = trace
+	g.Sites = sites
+
+	r.groups = append(r.groups, g)
+}
+
+// Hook records the error; it can be registered with OnWrap and OnPanic.
+func (r *Recorder) Hook(_ Frame, err error) {
+	r.Record(err)
+}
+
+// remove deletes the group from the order of groups.
+func (r *Recorder) remove(g *ErrorGroup) {
+	for i, cur := range r.groups {
+		if cur == g {
+			r.groups = append(r.groups[:i], r.groups[i+1:]...)
+
+			return
+		}
+	}
+}
+
+// Groups returns a copy of the recorded groups, most recently seen first.
+func (r *Recorder) Groups() []ErrorGroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groups := make([]ErrorGroup, len(r.groups))
+	for i, g := range r.groups {
+		groups[len(groups)-1-i] = *g
+	}
+
+	return groups
+}
+
+// Reset drops all recorded groups.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.groups = nil
+	r.index = make(map[string]*ErrorGroup, r.size)
+}
+
+// ServeHTTP renders the recorded groups, most recently seen first, with their full traces.
+// It responds with JSON if the request has the "format=json" query parameter
+// or accepts "application/json", and with an HTML page otherwise.
+// The "fingerprint" query parameter selects a single group.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	groups := r.Groups()
+
+	if fp := req.URL.Query().Get("fingerprint"); fp != "" {
+		selected := groups[:0]
+
+		for _, g := range groups {
+			if g.Fingerprint == fp {
+				selected = append(selected, g)
+			}
+		}
+
+		groups = selected
+	}
+
+	if req.URL.Query().Get("format") == "json" || strings.Contains(req.Header.Get("Accept"), "application/json") {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(groups)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = _recorderPage.Execute(w, groups)
+}
+
+var _recorderPage = template.Must(template.New("recorder").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>Recent errors</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; }
+table { border-collapse: collapse; }
+th, td { text-align: left; vertical-align: top; padding: 4px 8px; border-bottom: 1px solid #ddd; }
+pre { margin: 4px 0; }
+</style>
+</head>
+<body>
+<h1>Recent errors</h1>
+<p>{{len .}} groups, most recently seen first. <a href="?format=json">JSON</a></p>
+<table>
+<tr><th>Count</th><th>First seen</th><th>Last seen</th><th>Error</th></tr>
+{{- range .}}
+<tr>
+<td>{{.Count}}</td>
+<td>{{.FirstSeen.Format "2006-01-02 15:04:05"}}</td>
+<td>{{.LastSeen.Format "2006-01-02 15:04:05"}}</td>
+<td><a href="?fingerprint={{.Fingerprint}}">{{.Fingerprint}}</a> {{.Message}}<details><summary>trace</summary><pre>{{.Trace}}</pre>
+{{- range .Sites}}{{if .URL}}<div><a href="{{.URL}}">{{.FuncName}}:{{.Line}}</a></div>{{end}}{{end}}</details></td>
+</tr>
+{{- end}}
+</table>
+</body>
+</html>
+`)) //nolint: gochecknoglobals
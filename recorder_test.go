@@ -0,0 +1,233 @@
+package werr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRecorder returns a recorder whose clock advances by a second on every occurrence.
+func newTestRecorder(size int) *Recorder {
+	r := NewRecorder(size)
+	clock := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	r.now = func() time.Time {
+		clock = clock.Add(time.Second)
+
+		return clock
+	}
+
+	return r
+}
+
+func recordA(r *Recorder, err error) { r.Record(Wrapf(err, "a")) }
+
+func recordB(r *Recorder, err error) { r.Record(Wrapf(err, "b")) }
+
+func recordC(r *Recorder, err error) { r.Record(Wrapf(err, "c")) }
+
+func TestRecorder_Record(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test")
+
+	t.Run("groups by fingerprint", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestRecorder(10)
+		recordA(r, errTest)
+		recordB(r, errTest)
+		recordA(r, errTest)
+		r.Record(nil)
+
+		groups := r.Groups()
+		require.Len(t, groups, 2)
+
+		require.Equal(t, 2, groups[0].Count)
+		require.Equal(t, "a: test", groups[0].Message)
+		require.Equal(t, time.Date(2024, 1, 2, 3, 4, 6, 0, time.UTC), groups[0].FirstSeen)
+		require.Equal(t, time.Date(2024, 1, 2, 3, 4, 8, 0, time.UTC), groups[0].LastSeen)
+		require.Contains(t, groups[0].Trace, "recordA()\ta\ntest")
+
+		require.Equal(t, 1, groups[1].Count)
+		require.Equal(t, "b: test", groups[1].Message)
+	})
+
+	t.Run("drops least recently seen", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestRecorder(2)
+		recordA(r, errTest)
+		recordB(r, errTest)
+		recordA(r, errTest)
+		recordC(r, errTest)
+
+		groups := r.Groups()
+		require.Len(t, groups, 2)
+		require.Equal(t, "c: test", groups[0].Message)
+		require.Equal(t, "a: test", groups[1].Message)
+
+		recordB(r, errTest)
+		require.Equal(t, 1, r.Groups()[0].Count)
+	})
+
+	t.Run("redacts secrets", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRecorder(0)
+		r.Record(WrapArgs(errTest, Arg("token", Secret("t0k3n"))))
+
+		groups := r.Groups()
+		require.Len(t, groups, 1)
+		require.NotContains(t, groups[0].Message+groups[0].Trace, "t0k3n")
+	})
+
+	t.Run("when reset", func(t *testing.T) {
+		t.Parallel()
+
+		r := newTestRecorder(2)
+		recordA(r, errTest)
+		r.Reset()
+		require.Empty(t, r.Groups())
+
+		recordA(r, errTest)
+		require.Equal(t, 1, r.Groups()[0].Count)
+	})
+}
+
+// nolint: paralleltest
+func TestRecorder_Record_GlobalFormatter(t *testing.T) {
+	SetFormatter(TerminalFormatter(true))
+	defer SetFormatter(nil)
+
+	r := newTestRecorder(10)
+	recordA(r, errors.New("test"))
+
+	trace := r.Groups()[0].Trace
+	require.NotContains(t, trace, "\x1b[")
+	require.Contains(t, trace, "recordA()\ta\ntest")
+}
+
+// nolint: paralleltest
+func TestRecorder_Record_Permalinks(t *testing.T) {
+	defer SetPermalinks(Permalinks{})
+
+	SetPermalinks(Permalinks{
+		Revision: "main",
+		Modules:  map[string]Repository{"github.com/safeblock-dev/werr": {URL: "https://github.com/safeblock-dev/werr"}},
+	})
+
+	r := newTestRecorder(10)
+	recordA(r, errors.New("test"))
+
+	sites := r.Groups()[0].Sites
+	require.Len(t, sites, 1)
+	require.Equal(t, "github.com/safeblock-dev/werr.recordA", sites[0].FuncName)
+	require.Equal(t, 29, sites[0].Line)
+	require.Equal(t, "https://github.com/safeblock-dev/werr/blob/main/recorder_test.go#L29", sites[0].URL)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?format=json", nil))
+	require.Contains(t, rec.Body.String(), `"url":"https://github.com/safeblock-dev/werr/blob/main/recorder_test.go#L29"`)
+}
+
+// nolint: paralleltest
+func TestRecorder_Hook(t *testing.T) {
+	r := NewRecorder(10)
+	defer OnPanic(r.Hook)()
+
+	err := PanicToError("boom")
+
+	groups := r.Groups()
+	require.Len(t, groups, 1)
+	require.Equal(t, Fingerprint(err), groups[0].Fingerprint)
+}
+
+func TestRecorder_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRecorder(10)
+	recordA(r, errors.New("<script>"))
+	recordB(r, errors.New("b"))
+
+	t.Run("with html", func(t *testing.T) {
+		t.Parallel()
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+
+		body := rec.Body.String()
+		require.Contains(t, body, "2 groups")
+		require.Contains(t, body, "a: &lt;script&gt;")
+		require.NotContains(t, body, "<script>")
+		require.Less(t, strings.Index(body, "b: b"), strings.Index(body, "a: &lt;script&gt;"))
+	})
+
+	t.Run("with json", func(t *testing.T) {
+		t.Parallel()
+
+		for _, req := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "/debug/errors?format=json", nil),
+			func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, "/debug/errors", nil)
+				req.Header.Set("Accept", "application/json")
+
+				return req
+			}(),
+		} {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+			var groups []ErrorGroup
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &groups))
+			require.Equal(t, r.Groups(), groups)
+		}
+	})
+
+	t.Run("with site messages and args", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRecorder(1)
+		r.Record(WrapArgs(Wrapf(errors.New("test"), "load"), Arg("id", 7), Arg("token", Secret("t0k3n"))))
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors?format=json", nil))
+
+		var groups []struct {
+			Sites []map[string]any `json:"sites"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &groups))
+		require.Len(t, groups, 1)
+		require.Len(t, groups[0].Sites, 2)
+
+		require.Equal(t, map[string]any{"id": "7", "token": "‹×›"}, groups[0].Sites[0]["args"])
+		require.NotContains(t, groups[0].Sites[0], "message")
+		require.Equal(t, "load", groups[0].Sites[1]["message"])
+		require.NotContains(t, groups[0].Sites[1], "args")
+	})
+
+	t.Run("with fingerprint", func(t *testing.T) {
+		t.Parallel()
+
+		fp := r.Groups()[1].Fingerprint
+
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/errors?format=json&fingerprint="+fp, nil))
+
+		var groups []ErrorGroup
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &groups))
+		require.Len(t, groups, 1)
+		require.Equal(t, fp, groups[0].Fingerprint)
+	})
+}
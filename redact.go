@@ -0,0 +1,148 @@
+package werr
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// RedactedMarker replaces secret values in redacted output.
+const RedactedMarker = "‹×›"
+
+// Redaction selects whether secret values are hidden when an error is rendered.
+type Redaction uint8
+
+const (
+	// Redacted hides secret values and passes values of registered types through their redactors.
+	// This is the default mode, used by Error().
+	Redacted Redaction = iota
+	// Unredacted renders all values verbatim. Intended for local debugging only.
+	// The werr layers wrapped by another error, e.g. with fmt.Errorf and %w, are rendered
+	// verbatim too when the text of that error ends with the text of the error it wraps.
+	Unredacted
+)
+
+var _redactors sync.Map //nolint: gochecknoglobals
+
+// secretValue marks a value that must never appear in redacted output.
+type secretValue struct {
+	v any
+}
+
+// safeValue marks a value that is rendered verbatim even if its type has a registered redactor.
+type safeValue struct {
+	v any
+}
+
+// redactedValue is the redacted text of a value, rendered as is for every verb.
+type redactedValue string
+
+// Secret marks a value as sensitive. In redacted output it is rendered as RedactedMarker,
+// including when it is formatted directly with the fmt package.
+// Example: werr.Wrapf(err, werr.ArgsFormat, werr.Args(login, werr.Secret(password))).
+func Secret(v any) any {
+	return secretValue{v: v}
+}
+
+// Safe marks a value as safe to log, bypassing any redactor registered for its type.
+func Safe(v any) any {
+	return safeValue{v: v}
+}
+
+// RegisterRedactor registers a function that renders values of type T in redacted output,
+// e.g. to mask all but the domain of an email address. T must be a concrete type.
+// Registering a redactor for the same type again replaces the previous one.
+func RegisterRedactor[T any](fn func(T) string) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	_redactors.Store(typ, func(v any) string {
+		return fn(v.(T)) //nolint: forcetypeassert
+	})
+}
+
+// Format implements fmt.Formatter so that a secret is never revealed by the fmt package.
+func (secretValue) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, RedactedMarker)
+}
+
+// Format implements fmt.Formatter and renders the underlying value with the original verb and flags.
+func (s safeValue) Format(f fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(f, fmt.FormatString(f, verb), s.v)
+}
+
+// Format implements fmt.Formatter and renders the redacted text for every verb.
+func (r redactedValue) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, string(r))
+}
+
+// sprintfRedacted formats according to a format specifier the arguments redacted by redactArgs
+// and returns the resulting message, redacted and unredacted.
+// The unredacted message is empty if it equals the redacted one.
+func sprintfRedacted(format string, a, redacted []any) (string, string) {
+	msg := fmt.Sprintf(format, redacted...)
+
+	unredacted, _ := redactArgs(a, Unredacted)
+	if raw := fmt.Sprintf(format, unredacted...); raw != msg {
+		return msg, raw
+	}
+
+	return msg, ""
+}
+
+// redactArgs returns a copy of args prepared for formatting in the given mode.
+// Slices produced by Args are processed recursively.
+// If no argument needs to be replaced, args is returned as is along with false.
+func redactArgs(args []any, mode Redaction) ([]any, bool) {
+	var out []any
+
+	for i, arg := range args {
+		v, ok := redactValue(arg, mode)
+		if !ok {
+			continue
+		}
+
+		if out == nil {
+			out = make([]any, len(args))
+			copy(out, args)
+		}
+
+		out[i] = v
+	}
+
+	if out == nil {
+		return args, false
+	}
+
+	return out, true
+}
+
+// redactValue returns the value prepared for formatting in the given mode and whether it differs from v.
+func redactValue(v any, mode Redaction) (any, bool) {
+	switch val := v.(type) {
+	case nil:
+		return nil, false
+	case secretValue:
+		if mode == Unredacted {
+			return val.v, true
+		}
+
+		// A secret renders RedactedMarker on its own; v is returned to avoid boxing val again.
+		return v, true
+	case safeValue:
+		return val.v, true
+	case []any:
+		return redactArgs(val, mode)
+	}
+
+	if mode == Unredacted {
+		return v, false
+	}
+
+	fn, ok := _redactors.Load(reflect.TypeOf(v))
+	if !ok {
+		return v, false
+	}
+
+	return redactedValue(fn.(func(any) string)(v)), true //nolint: forcetypeassert
+}
@@ -0,0 +1,117 @@
+package werr_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+type testEmail string
+
+type testToken string
+
+func TestRedaction(t *testing.T) {
+	t.Parallel()
+
+	werr.RegisterRedactor(func(v testEmail) string {
+		_, domain, _ := strings.Cut(string(v), "@")
+
+		return "***@" + domain
+	})
+
+	originalErr := errors.New("original error")
+	unredacted := werr.Formatter{Redaction: werr.Unredacted}
+
+	t.Run("when secret", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, "login=%s password=%s", "admin", werr.Secret("hunter2"))
+
+		require.Equal(t, "login=admin password=‹×›", err.(werr.Error).Message())
+		require.NotContains(t, err.Error(), "hunter2")
+		require.Contains(t, unredacted.Format(err), "login=admin password=hunter2")
+	})
+
+	t.Run("when secret in args", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, werr.ArgsFormat, werr.Args("admin", werr.Secret(testToken("t0k3n")), 1))
+
+		require.Equal(t, "args=[admin ‹×› 1]", err.(werr.Error).Message())
+		require.Contains(t, unredacted.Format(err), "args=[admin t0k3n 1]")
+	})
+
+	t.Run("when secret with non-string verb", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, "pin=%04d", werr.Secret(1234))
+
+		require.Equal(t, "pin=‹×›", err.(werr.Error).Message())
+		require.Contains(t, unredacted.Format(err), "pin=1234")
+	})
+
+	t.Run("when registered redactor", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, werr.ArgsFormat, werr.Args(testEmail("john@example.com")))
+
+		require.Equal(t, "args=[***@example.com]", err.(werr.Error).Message())
+		require.Contains(t, unredacted.Format(err), "args=[john@example.com]")
+	})
+
+	t.Run("when safe", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, "email=%s", werr.Safe(testEmail("support@example.com")))
+
+		require.Equal(t, "email=support@example.com", err.(werr.Error).Message())
+	})
+
+	t.Run("when wrapped by another error", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, "token=%s", werr.Secret(testToken("t0k3n")))
+		err = fmt.Errorf("retrying: %w", err)
+
+		require.NotContains(t, err.Error(), "t0k3n")
+		require.Contains(t, unredacted.Format(err), "retrying: ")
+		require.Contains(t, unredacted.Format(err), "token=t0k3n")
+
+		err = werr.Wrapf(err, "user=%s", "john")
+
+		formatted := unredacted.Format(err)
+		require.Contains(t, formatted, "user=john")
+		require.Contains(t, formatted, "\nretrying: ")
+		require.Contains(t, formatted, "token=t0k3n")
+		require.NotContains(t, formatted, "‹×›")
+
+		formatted = werr.Formatter{Redaction: werr.Unredacted, Fn: werr.SingleLineFormatter()}.Format(err)
+		require.Equal(t, "user=john: retrying: token=t0k3n: original error", formatted)
+	})
+
+	t.Run("when wrap chain", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrapf(originalErr, "token=%s", werr.Secret(testToken("t0k3n")))
+		err = werr.Wrapf(err, "user=%s", testEmail("john@example.com"))
+
+		require.NotContains(t, err.Error(), "t0k3n")
+		require.NotContains(t, err.Error(), "john@")
+
+		raw := unredacted.Format(err)
+		require.Contains(t, raw, "token=t0k3n")
+		require.Contains(t, raw, "user=john@example.com")
+	})
+
+	t.Run("when formatted directly", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "‹×›", fmt.Sprintf("%v", werr.Secret("hunter2")))
+		require.Equal(t, "  42", fmt.Sprintf("%4d", werr.Safe(42)))
+	})
+}
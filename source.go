@@ -0,0 +1,188 @@
+package werr
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Limits of the source cache used by SourceFormatter.
+const (
+	maxSourceFileSize = 1 << 20 // maxSourceFileSize is the size of the largest source file read, in bytes.
+	maxSourceFiles    = 64      // maxSourceFiles is the number of source files kept in memory.
+)
+
+// _sources caches the lines of source files read by SourceFormatter.
+var _sources = newSourceCache(maxSourceFiles, maxSourceFileSize) //nolint: gochecknoglobals
+
+// SourceOption configures SourceFormatter.
+type SourceOption func(*sourceFormatter)
+
+// WithContextLines sets the number of source lines printed before and after a wrap site, 2 by default.
+func WithContextLines(n int) SourceOption {
+	return func(s *sourceFormatter) {
+		if n >= 0 {
+			s.context = n
+		}
+	}
+}
+
+// SourceFormatter returns a chain formatter for local debugging: it renders every wrap site
+// in the layout of the default formatter followed by the surrounding source lines,
+// with the line of the wrap site marked by ">":
+//
+//	main/load.go:84	load()	reading file
+//		  83		data, err := os.ReadFile(name)
+//		> 84		return werr.Wrapf(err, "reading file")
+//		  85	}
+//	open config.yaml: no such file or directory
+//
+// Source files are read from the paths recorded at compile time and kept in a bounded cache;
+// files larger than 1 MiB are not read. Wrap sites whose source is not available, e.g. in
+// production containers, are rendered without source lines.
+func SourceFormatter(opts ...SourceOption) ChainFormatter {
+	s := sourceFormatter{context: 2, cache: _sources} //nolint: mnd
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	return s
+}
+
+// sourceFormatter renders traces with source snippets.
+type sourceFormatter struct {
+	context int
+	cache   *sourceCache
+}
+
+func (s sourceFormatter) AppendChain(dst []byte, c Chain) []byte {
+	for _, f := range c.Frames {
+		dst = appendLine(dst, f.File, f.Line, f.FuncName, f.Message, f.Args, c.Redaction)
+		dst = s.appendSnippet(dst, f.File, f.Line)
+	}
+
+	if c.Cause != nil {
+		dst = append(dst, c.Cause.Error()...)
+	}
+
+	return dst
+}
+
+// appendSnippet appends the source lines around the line of the file, if they are available.
+func (s sourceFormatter) appendSnippet(dst []byte, file string, line int) []byte {
+	lines := s.cache.lines(file)
+	if line < 1 || line > len(lines) {
+		return dst
+	}
+
+	first, last := line-s.context, line+s.context
+	if first < 1 {
+		first = 1
+	}
+
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	width := len(strconv.Itoa(last))
+
+	for n := first; n <= last; n++ {
+		marker := "\t  "
+		if n == line {
+			marker = "\t> "
+		}
+
+		dst = append(dst, marker...)
+
+		for pad := width - len(strconv.Itoa(n)); pad > 0; pad-- {
+			dst = append(dst, ' ')
+		}
+
+		dst = strconv.AppendInt(dst, int64(n), 10) //nolint: mnd
+
+		if text := lines[n-1]; text != "" {
+			dst = append(append(dst, '\t'), text...)
+		}
+
+		dst = append(dst, '\n')
+	}
+
+	return dst
+}
+
+// sourceCache keeps the lines of recently read source files, including files that could not
+// be read, so that unavailable sources are not looked up again. When it is full, the file read
+// first is evicted.
+type sourceCache struct {
+	maxFiles int
+	maxSize  int64
+
+	mu    sync.Mutex
+	files map[string][]string
+	order []string
+}
+
+func newSourceCache(maxFiles int, maxSize int64) *sourceCache {
+	return &sourceCache{
+		maxFiles: maxFiles,
+		maxSize:  maxSize,
+		files:    make(map[string][]string, maxFiles),
+	}
+}
+
+// lines returns the lines of the file, or nil if it is not available.
+func (c *sourceCache) lines(file string) []string {
+	c.mu.Lock()
+	lines, ok := c.files[file]
+	c.mu.Unlock()
+
+	if ok {
+		return lines
+	}
+
+	lines = c.read(file)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.files[file]; !ok {
+		if len(c.order) >= c.maxFiles {
+			delete(c.files, c.order[0])
+			c.order = c.order[1:]
+		}
+
+		c.files[file] = lines
+		c.order = append(c.order, file)
+	}
+
+	return lines
+}
+
+// read returns the lines of the file, or nil if it cannot be read or exceeds the size limit.
+func (c *sourceCache) read(file string) []string {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() || fi.Size() > c.maxSize {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, c.maxSize))
+	if err != nil {
+		return nil
+	}
+
+	lines := strings.Split(string(data), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+
+	return lines
+}
@@ -0,0 +1,113 @@
+package werr
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sourceWrap(err error) error {
+	return Wrapf(err, "source %d", 1)
+}
+
+func TestSourceFormatter(t *testing.T) {
+	t.Parallel()
+
+	errTest := errors.New("test")
+
+	t.Run("with source", func(t *testing.T) {
+		t.Parallel()
+
+		err := sourceWrap(errTest)
+		e, ok := err.(Error) //nolint: errorlint
+		require.True(t, ok)
+
+		out := Formatter{Chain: SourceFormatter(WithContextLines(1))}.Format(err)
+		line := strconv.Itoa(e.Line())
+		prev, next := strconv.Itoa(e.Line()-1), strconv.Itoa(e.Line()+1)
+
+		require.Equal(t, "github.com/safeblock-dev/werr/source_test.go:"+line+"\tsourceWrap()\tsource 1\n"+
+			"\t  "+prev+"\tfunc sourceWrap(err error) error {\n"+
+			"\t> "+line+"\t\treturn Wrapf(err, \"source %d\", 1)\n"+
+			"\t  "+next+"\t}\n"+
+			"test", out)
+	})
+
+	t.Run("without context", func(t *testing.T) {
+		t.Parallel()
+
+		inner := sourceWrap(errTest)
+		e := Error{file: "source_test.go", line: 1, funcName: "werr.main", err: inner, msg: "msg"}
+		line := strconv.Itoa(inner.(Error).Line()) //nolint: errorlint, forcetypeassert
+
+		require.Equal(t, "werr/source_test.go:1\tmain()\tmsg\n"+
+			"\t> 1\tpackage werr\n"+
+			"github.com/safeblock-dev/werr/source_test.go:"+line+"\tsourceWrap()\tsource 1\n"+
+			"\t> "+line+"\t\treturn Wrapf(err, \"source %d\", 1)\n"+
+			"test", Formatter{Chain: SourceFormatter(WithContextLines(0))}.Format(e))
+	})
+
+	t.Run("when source not available", func(t *testing.T) {
+		t.Parallel()
+
+		e := Error{file: "/nonexistent/main.go", funcName: "main.main", line: 42, err: errTest, msg: "load"}
+		require.Equal(t, "main/main.go:42\tmain()\tload\ntest", Formatter{Chain: SourceFormatter()}.Format(e))
+
+		e.file, e.line = "source_test.go", 100000
+		require.Equal(t, "main/source_test.go:100000\tmain()\tload\ntest", Formatter{Chain: SourceFormatter()}.Format(e))
+	})
+}
+
+func TestSourceCache(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+
+		return file
+	}
+
+	t.Run("reads lines", func(t *testing.T) {
+		t.Parallel()
+
+		c := newSourceCache(2, 100)
+		file := write("crlf.go", "a\r\nb\n")
+
+		require.Equal(t, []string{"a", "b", ""}, c.lines(file))
+	})
+
+	t.Run("with size limit", func(t *testing.T) {
+		t.Parallel()
+
+		c := newSourceCache(2, 10)
+		require.Nil(t, c.lines(write("large.go", strings.Repeat("x", 11))))
+		require.Equal(t, []string{"0123456789"}, c.lines(write("small.go", "0123456789")))
+		require.Nil(t, c.lines(dir))
+	})
+
+	t.Run("evicts first read", func(t *testing.T) {
+		t.Parallel()
+
+		c := newSourceCache(2, 100)
+		a, b, d := write("evict_a.go", "a"), write("evict_b.go", "b"), write("evict_c.go", "c")
+
+		require.Equal(t, []string{"a"}, c.lines(a))
+		require.Equal(t, []string{"b"}, c.lines(b))
+
+		// Cached files are not read again.
+		require.NoError(t, os.Remove(a))
+		require.Equal(t, []string{"a"}, c.lines(a))
+
+		require.Equal(t, []string{"c"}, c.lines(d))
+		require.Equal(t, []string{b, d}, c.order)
+		require.Nil(t, c.lines(a))
+	})
+}
@@ -0,0 +1,125 @@
+package werr
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// StackFrame is the program counter of a wrap site.
+// It mirrors github.com/pkg/errors.Frame: the value is a return address,
+// as reported by runtime.Callers.
+type StackFrame uintptr
+
+// StackTrace is a stack of wrap sites synthesized from an error chain:
+// the innermost wrap site comes first and the outermost one last,
+// the same order as a call stack reported by runtime.Callers.
+// It mirrors the layout of github.com/pkg/errors.StackTrace, but it is a distinct type:
+// code asserting interface{ StackTrace() errors.StackTrace } from github.com/pkg/errors
+// does not match werr errors. Only error reporters finding the StackTrace method by
+// reflection and reading the program counters from its result, such as the Sentry SDK,
+// pick up the stack.
+type StackTrace []StackFrame
+
+// StackTrace returns the wrap sites of the error chain as a stack trace, outermost last.
+func (e Error) StackTrace() StackTrace {
+	pcs := e.Callers()
+	st := make(StackTrace, len(pcs))
+
+	for i, pc := range pcs {
+		st[i] = StackFrame(pc)
+	}
+
+	return st
+}
+
+// Callers returns the program counters of the wrap sites of the error chain, outermost last.
+// The values are return addresses and can be passed to runtime.CallersFrames.
+func (e Error) Callers() []uintptr {
+	var pcs []uintptr
+
+	for err := error(e); err != nil; err = errors.Unwrap(err) {
+		if w, ok := err.(Error); ok && w.pc != 0 { //nolint: errorlint
+			pcs = append(pcs, w.pc)
+		}
+	}
+
+	// The chain is walked outermost first, a stack is innermost first.
+	for i, j := 0, len(pcs)-1; i < j; i, j = i+1, j-1 {
+		pcs[i], pcs[j] = pcs[j], pcs[i]
+	}
+
+	return pcs
+}
+
+// frame resolves the program counter into a runtime.Frame.
+func (f StackFrame) frame() runtime.Frame {
+	frame, _ := runtime.CallersFrames([]uintptr{uintptr(f)}).Next()
+
+	return frame
+}
+
+// Format formats the frame according to the fmt.Formatter interface,
+// following the conventions of github.com/pkg/errors:
+//
+//	%s    source file
+//	%d    source line
+//	%n    function name
+//	%v    equivalent to %s:%d
+//	%+s   function name and path of source file relative to the compile time GOPATH
+//	%+v   equivalent to %+s:%d
+func (f StackFrame) Format(s fmt.State, verb rune) {
+	frame := f.frame()
+
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, frame.Function+"\n\t"+frame.File)
+		} else {
+			_, _ = io.WriteString(s, path.Base(frame.File))
+		}
+	case 'd':
+		_, _ = io.WriteString(s, strconv.Itoa(frame.Line))
+	case 'n':
+		name := path.Base(frame.Function)
+		_, _ = io.WriteString(s, name[strings.Index(name, ".")+1:])
+	case 'v':
+		f.Format(s, 's')
+		_, _ = io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// Format formats the stack of frames according to the fmt.Formatter interface.
+// The %+v verb prints every frame on its own line with function name, file and line.
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for _, f := range st {
+				_, _ = io.WriteString(s, "\n")
+				f.Format(s, verb)
+			}
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, "[")
+
+		for i, f := range st {
+			if i > 0 {
+				_, _ = io.WriteString(s, " ")
+			}
+
+			f.Format(s, verb)
+		}
+
+		_, _ = io.WriteString(s, "]")
+	}
+}
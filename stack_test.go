@@ -0,0 +1,122 @@
+package werr_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// The helpers are not inlined: runtime.CallersFrames expands a wrap site inlined
+// into its caller into several frames.
+
+//go:noinline
+func stackInner() error { return werr.Wrap(errors.New("original error")) }
+
+//go:noinline
+func stackMiddle() error { return fmt.Errorf("fmt wrap: %w", stackInner()) }
+
+//go:noinline
+func stackOuter() error { return werr.Wrapf(stackMiddle(), "outer") }
+
+// sentryExtractPCs mimics the reflection-based extraction of the Sentry Go SDK,
+// which calls a StackTrace method and reads every element of the result as a program counter.
+func sentryExtractPCs(err error) []uintptr {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+
+	stacktrace := method.Call(nil)[0]
+	if stacktrace.Kind() != reflect.Slice {
+		return nil
+	}
+
+	pcs := make([]uintptr, 0, stacktrace.Len())
+
+	for i := 0; i < stacktrace.Len(); i++ {
+		if pc := stacktrace.Index(i); pc.Kind() == reflect.Uintptr {
+			pcs = append(pcs, uintptr(pc.Uint()))
+		}
+	}
+
+	return pcs
+}
+
+// callersExtractFunctions mimics loggers that look for a Callers method
+// and resolve the program counters with runtime.CallersFrames.
+func callersExtractFunctions(err error) []string {
+	var c interface{ Callers() []uintptr }
+	if !errors.As(err, &c) {
+		return nil
+	}
+
+	return framesFunctions(c.Callers())
+}
+
+func framesFunctions(pcs []uintptr) []string {
+	var funcs []string
+
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		frame, more := frames.Next()
+		funcs = append(funcs, frame.Function)
+
+		if !more {
+			return funcs
+		}
+	}
+}
+
+func TestError_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	exp := []string{
+		"github.com/safeblock-dev/werr_test.stackInner",
+		"github.com/safeblock-dev/werr_test.stackOuter",
+	}
+
+	t.Run("sentry extraction", func(t *testing.T) {
+		t.Parallel()
+
+		pcs := sentryExtractPCs(stackOuter())
+		require.Equal(t, exp, framesFunctions(pcs))
+	})
+
+	t.Run("callers extraction", func(t *testing.T) {
+		t.Parallel()
+
+		err := fmt.Errorf("outside: %w", stackOuter())
+		require.Equal(t, exp, callersExtractFunctions(err))
+	})
+
+	t.Run("StackTrace method interface", func(t *testing.T) {
+		t.Parallel()
+
+		var st interface{ StackTrace() werr.StackTrace }
+
+		require.ErrorAs(t, stackOuter(), &st)
+		require.Len(t, st.StackTrace(), 2)
+	})
+
+	t.Run("format", func(t *testing.T) {
+		t.Parallel()
+
+		st := stackOuter().(werr.Error).StackTrace()
+
+		require.Equal(t, "[stack_test.go:20 stack_test.go:26]", fmt.Sprintf("%v", st))
+		require.Equal(t, "stackInner", fmt.Sprintf("%n", st[0]))
+
+		lines := strings.Split(fmt.Sprintf("%+v", st), "\n")
+		require.Len(t, lines, 5)
+		require.Equal(t, "github.com/safeblock-dev/werr_test.stackInner", lines[1])
+		require.True(t, strings.HasSuffix(lines[2], "/stack_test.go:20"))
+	})
+}
@@ -0,0 +1,139 @@
+package werr
+
+import (
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// TemplateData is the data a TemplateFormatter template is executed with.
+type TemplateData struct {
+	Frames []TemplateFrame // Frames are the wrap sites of the chain, outermost first.
+	Cause  string          // Cause is the text of the first error of the chain that is not wrapped by werr.
+}
+
+// TemplateFrame is a wrap site of the chain passed to a TemplateFormatter template.
+// Values are rendered in the redaction mode of the formatter.
+type TemplateFrame struct {
+	Package  string          // Package is the import path of the package, e.g. "example.com/app/user".
+	Func     string          // Func is the function name within the package, e.g. "(*Store).Load" or "Load.func1".
+	FuncName string          // FuncName is the fully qualified function name, e.g. "example.com/app/user.(*Store).Load".
+	File     string          // File is the path of the source file.
+	Line     int             // Line is the line number of the wrap site.
+	Message  string          // Message is the additional message, without the named arguments.
+	Fields   []TemplateField // Fields are the named arguments, see WrapArgs.
+	URL      string          // URL is the permalink of the wrap site, if available; see SetPermalinks.
+}
+
+// TemplateField is a named argument of a wrap site.
+type TemplateField struct {
+	Key   string // Key is the name of the argument.
+	Value string // Value is the value rendered with the %+v verb.
+}
+
+// TemplateFormatter returns a formatter rendering the whole chain with a text/template,
+// so that layouts can be defined in configuration files. The template is executed with
+// TemplateData at the outermost layer. Besides the text/template built-ins, it can use:
+//
+//	basename    the last element of a path: {{basename .File}}
+//	trimModule  the path without the main module prefix: {{trimModule .Package}}
+//	indent      every line prefixed with a string: {{indent "  " .Cause}}
+//
+// For example, the layout of the default formatter without locations:
+//
+//	{{range .Frames}}{{.Func}}(){{with .Message}}: {{.}}{{end}}{{range .Fields}} {{.Key}}={{.Value}}{{end}}
+//	{{end}}{{.Cause}}
+//
+// If the template fails to execute, the error is rendered by the default formatter
+// followed by the template error.
+func TemplateFormatter(tmpl string) (FormatFn, error) {
+	t, err := template.New("werr").Funcs(template.FuncMap{
+		"basename":   path.Base,
+		"trimModule": trimModule,
+		"indent":     indent,
+	}).Parse(tmpl)
+	if err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+
+	return func(file string, line int, funcName string, inner error, msg string) string {
+		layers, cause := chainLayers(file, line, funcName, inner, msg)
+
+		data := TemplateData{Frames: make([]TemplateFrame, len(layers))}
+		for i, l := range layers {
+			data.Frames[i] = templateFrame(l)
+		}
+
+		if cause != nil {
+			data.Cause = cause.Error()
+		}
+
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			b.Reset()
+
+			for _, l := range layers {
+				source, fn := location(l.file, l.line, l.funcName)
+				b.WriteString(strings.TrimSuffix(source+"\t"+fn+"\t"+l.msg, "\t") + "\n")
+			}
+
+			b.WriteString(data.Cause + "\n" + err.Error())
+		}
+
+		return b.String()
+	}, nil
+}
+
+// templateFrame returns the template data of a layer.
+func templateFrame(l layer) TemplateFrame {
+	frame := Frame{FuncName: l.funcName, File: l.file, Line: l.line}
+
+	f := TemplateFrame{
+		Package:  frame.Package(),
+		Func:     frame.Func(),
+		FuncName: l.funcName,
+		File:     l.file,
+		Line:     l.line,
+		Message:  l.text,
+		URL:      frame.Permalink(),
+	}
+
+	for _, arg := range l.args {
+		f.Fields = append(f.Fields, TemplateField{Key: arg.Key, Value: formatValue(arg.Value, l.mode)})
+	}
+
+	return f
+}
+
+var (
+	_mainModule     string    //nolint: gochecknoglobals
+	_mainModuleOnce sync.Once //nolint: gochecknoglobals
+)
+
+// trimModule strips the path of the main module from an import path or a qualified name.
+func trimModule(s string) string {
+	_mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			_mainModule = info.Main.Path
+		}
+	})
+
+	if mod := _mainModule; mod != "" {
+		if rest, ok := strings.CutPrefix(s, mod+"/"); ok {
+			return rest
+		}
+	}
+
+	return s
+}
+
+// indent prefixes every line of s.
+func indent(prefix, s string) string {
+	if s == "" {
+		return s
+	}
+
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
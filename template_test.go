@@ -0,0 +1,86 @@
+package werr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateFormatter(t *testing.T) {
+	t.Parallel()
+
+	fields := []Field{Arg("id", 7), Arg("token", Secret("s3cr3t"))}
+	err := Error{
+		file:     "/src/app/main.go",
+		funcName: "example.com/app.main",
+		line:     42,
+		msg:      "loading config",
+		err: Error{
+			file:     "/src/app/user/store.go",
+			funcName: "example.com/app/user.(*Store).Load",
+			line:     84,
+			args:     &fields,
+			err:      errors.New("no such user"),
+		},
+	}
+
+	t.Run("with frames", func(t *testing.T) {
+		t.Parallel()
+
+		fn, tmplErr := TemplateFormatter(
+			`{{range .Frames}}{{basename .File}}:{{.Line}} {{.Package}} {{.Func}}{{with .Message}}: {{.}}{{end}}` +
+				`{{range .Fields}} {{.Key}}={{.Value}}{{end}}` + "\n" + `{{end}}{{indent "  " .Cause}}`)
+		require.NoError(t, tmplErr)
+
+		exp := "main.go:42 example.com/app main: loading config\n" +
+			"store.go:84 example.com/app/user (*Store).Load id=7 token=‹×›\n" +
+			"  no such user"
+		require.Equal(t, exp, err.Format(fn))
+	})
+
+	t.Run("with formatter", func(t *testing.T) {
+		t.Parallel()
+
+		fn, tmplErr := TemplateFormatter(`{{range .Frames}}{{.FuncName}}{{range .Fields}} {{.Key}}={{.Value}}{{end}}; {{end}}{{.Cause}}`)
+		require.NoError(t, tmplErr)
+
+		exp := "example.com/app.main; example.com/app/user.(*Store).Load id=7 token=s3cr3t; no such user"
+		require.Equal(t, exp, Formatter{Redaction: Unredacted, Fn: fn}.Format(err))
+	})
+
+	t.Run("with invalid template", func(t *testing.T) {
+		t.Parallel()
+
+		fn, tmplErr := TemplateFormatter(`{{range .Frames}}`)
+		require.Error(t, tmplErr)
+		require.Nil(t, fn)
+	})
+
+	t.Run("when execution fails", func(t *testing.T) {
+		t.Parallel()
+
+		fn, tmplErr := TemplateFormatter(`{{.Unknown}}`)
+		require.NoError(t, tmplErr)
+
+		exp := "example.com/app/main.go:42\tmain()\tloading config\n" +
+			"example.com/app/user.(*Store)/store.go:84\tLoad()\tid=7 token=‹×›\n" +
+			"no such user\n" +
+			`template: werr:1:2: executing "werr" at <.Unknown>: can't evaluate field Unknown in type werr.TemplateData`
+		require.Equal(t, exp, err.Format(fn))
+	})
+}
+
+func TestTrimModule(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "werrtest", trimModule("github.com/safeblock-dev/werr/werrtest"))
+	require.Equal(t, "example.com/app/user", trimModule("example.com/app/user"))
+}
+
+func TestIndent(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "  a\n  b", indent("  ", "a\nb"))
+	require.Empty(t, indent("  ", ""))
+}
@@ -0,0 +1,103 @@
+package werr
+
+import (
+	"os"
+	"strings"
+)
+
+// ANSI escape sequences used by TerminalFormatter.
+const (
+	ansiReset    = "\x1b[0m"
+	ansiLocation = "\x1b[36m"   // cyan
+	ansiFunc     = "\x1b[1;34m" // bold blue
+	ansiMessage  = "\x1b[33m"   // yellow
+	ansiCause    = "\x1b[1;31m" // bold red
+)
+
+// TerminalFormatter returns a formatter for terminal output. The whole trace is rendered
+// at the outermost layer with the location, function and message columns aligned across
+// frames, followed by the root cause:
+//
+//	main/main.go:42  main()  loading config
+//	main/load.go:84  load()
+//	open config.yaml: no such file or directory
+//
+// If color is true, the columns and the root cause are highlighted with ANSI escape sequences.
+// Use ColorEnabled to decide whether an output supports colors. The formatter can be set
+// globally with SetFormatter or used for a single Formatter:
+//
+//	werr.SetFormatter(werr.TerminalFormatter(werr.ColorEnabled(os.Stderr)))
+func TerminalFormatter(color bool) FormatFn {
+	t := terminal{color: color}
+
+	return t.format
+}
+
+// ColorEnabled reports whether colored output should be written to the file:
+// the file is a terminal, the NO_COLOR environment variable is not set (https://no-color.org)
+// and TERM is not "dumb".
+func ColorEnabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	fi, err := f.Stat()
+
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// terminal renders aligned traces for terminals.
+type terminal struct {
+	color bool
+}
+
+func (t terminal) format(file string, line int, funcName string, err error, msg string) string {
+	layers, cause := chainLayers(file, line, funcName, err, msg)
+
+	sources := make([]string, len(layers))
+	funcs := make([]string, len(layers))
+
+	var sourceWidth, funcWidth int
+
+	for i, l := range layers {
+		sources[i], funcs[i] = location(l.file, l.line, l.funcName)
+
+		if len(sources[i]) > sourceWidth {
+			sourceWidth = len(sources[i])
+		}
+
+		if len(funcs[i]) > funcWidth {
+			funcWidth = len(funcs[i])
+		}
+	}
+
+	var b strings.Builder
+
+	for i, l := range layers {
+		b.WriteString(t.paint(ansiLocation, sources[i]))
+		b.WriteString(strings.Repeat(" ", sourceWidth-len(sources[i])+2)) //nolint: mnd
+		b.WriteString(t.paint(ansiFunc, funcs[i]))
+
+		if l.msg != "" {
+			b.WriteString(strings.Repeat(" ", funcWidth-len(funcs[i])+2)) //nolint: mnd
+			b.WriteString(t.paint(ansiMessage, l.msg))
+		}
+
+		b.WriteString("\n")
+	}
+
+	if cause != nil {
+		b.WriteString(t.paint(ansiCause, cause.Error()))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// paint wraps the text in the escape sequence if colors are enabled.
+func (t terminal) paint(code, text string) string {
+	if !t.color || text == "" {
+		return text
+	}
+
+	return code + text + ansiReset
+}
@@ -0,0 +1,94 @@
+package werr
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerminalFormatter(t *testing.T) {
+	t.Parallel()
+
+	err := Error{
+		file:     "/src/main.go",
+		funcName: "main.main",
+		line:     42,
+		err: fmt.Errorf("load: %w", Error{
+			file:     "/src/main.go",
+			funcName: "main.load",
+			line:     84,
+			err:      errors.New("original error"),
+		}),
+		msg: "additional message",
+	}
+
+	deep := Error{
+		file:     "/src/main.go",
+		funcName: "main.main",
+		line:     42,
+		err: Error{
+			file:     "/src/config/config.go",
+			funcName: "example.com/app/config.(*Loader).Load",
+			line:     7,
+			err:      errors.New("original error"),
+			msg:      "path=" + RedactedMarker,
+			rawMsg:   "path=/etc/app.yaml",
+			format:   "path=%s",
+		},
+	}
+
+	t.Run("without color", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t,
+			"main/main.go:42                               main()\n"+
+				"example.com/app/config.(*Loader)/config.go:7  Load()  path=‹×›\n"+
+				"original error",
+			deep.Format(TerminalFormatter(false)))
+	})
+
+	t.Run("with color", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t,
+			"\x1b[36mmain/main.go:42\x1b[0m  \x1b[1;34mmain()\x1b[0m  \x1b[33madditional message\x1b[0m\n"+
+				"\x1b[1;31mload: main/main.go:84\tload()\noriginal error\x1b[0m",
+			err.Format(TerminalFormatter(true)))
+	})
+
+	t.Run("with formatter", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t,
+			"main/main.go:42                               main()\n"+
+				"example.com/app/config.(*Loader)/config.go:7  Load()  path=/etc/app.yaml\n"+
+				"original error",
+			Formatter{Redaction: Unredacted, Fn: TerminalFormatter(false)}.Format(deep))
+	})
+}
+
+// nolint: paralleltest
+func TestColorEnabled(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	require.NoError(t, err)
+
+	defer f.Close()
+
+	t.Run("when not a terminal", func(t *testing.T) {
+		require.False(t, ColorEnabled(f))
+	})
+
+	t.Run("when NO_COLOR is set", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		require.False(t, ColorEnabled(os.Stdout))
+	})
+
+	t.Run("when TERM is dumb", func(t *testing.T) {
+		t.Setenv("TERM", "dumb")
+		require.False(t, ColorEnabled(os.Stdout))
+	})
+}
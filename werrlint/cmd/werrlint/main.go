@@ -0,0 +1,23 @@
+// Command werrlint runs the werr analyzers.
+//
+// It can be run standalone or as a vet tool:
+//
+//	werrlint ./...
+//	go vet -vettool=$(which werrlint) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/safeblock-dev/werr/werrlint/errcompare"
+	"github.com/safeblock-dev/werr/werrlint/unwrapped"
+	"github.com/safeblock-dev/werr/werrlint/wrapf"
+)
+
+func main() {
+	multichecker.Main(
+		unwrapped.Analyzer,
+		errcompare.Analyzer,
+		wrapf.Analyzer,
+	)
+}
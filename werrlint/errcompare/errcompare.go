@@ -0,0 +1,379 @@
+// Package errcompare defines an Analyzer that reports direct comparisons,
+// type assertions and type switches on errors wrapped by werr.
+package errcompare
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/safeblock-dev/werr/werrlint/internal/analysisutil"
+)
+
+// Doc is the documentation of the analyzer.
+const Doc = `report direct comparisons on errors wrapped by werr
+
+werr.Wrap returns a new werr.Error value, so once an error passes through
+werr, comparing it with == or != and asserting its type stop working:
+
+	err := load() // load returns werr.Wrap(ErrNotFound)
+	if err == ErrNotFound { // never true, use errors.Is(err, ErrNotFound)
+	}
+	if e, ok := err.(*MyError); ok { // never ok, use errors.As(err, &e)
+	}
+
+An error is considered wrapped if it is the result of a werr function or of
+a function that returns one, including functions of imported packages.
+Comparisons are fixed with errors.Is; type assertions and type switches
+are reported with a hint to use errors.As, unless they only match
+interfaces or werr.Error itself. A diagnostic is suppressed by a
+//werr:ignore comment on the same line or on the line above it.`
+
+// Analyzer reports direct comparisons on errors wrapped by werr.
+var Analyzer = &analysis.Analyzer{ //nolint: gochecknoglobals
+	Name:      "errcompare",
+	Doc:       Doc,
+	URL:       "https://pkg.go.dev/github.com/safeblock-dev/werr/werrlint/errcompare",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(wrapsFact)},
+}
+
+// wrapsFact marks a function that may return an error wrapped by werr.
+type wrapsFact struct {
+	Via string // Via is the werr function or the function through which the error is wrapped.
+}
+
+// AFact implements analysis.Fact.
+func (*wrapsFact) AFact() {}
+
+func (f *wrapsFact) String() string {
+	return "wraps(" + f.Via + ")"
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	funcs := collectFuncs(pass)
+
+	// Propagate facts through calls within the package until nothing changes.
+	for changed := true; changed; {
+		changed = false
+
+		for _, fn := range funcs {
+			if pass.ImportObjectFact(fn.obj, new(wrapsFact)) {
+				continue
+			}
+
+			if via := returnsWrapped(pass, fn); via != "" {
+				pass.ExportObjectFact(fn.obj, &wrapsFact{Via: via})
+
+				changed = true
+			}
+		}
+	}
+
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+
+		c := &checker{
+			pass:    pass,
+			file:    file,
+			ignored: analysisutil.Ignored(pass.Fset, file),
+		}
+
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				c.checkBody(fn.Body)
+			}
+		}
+	}
+
+	return nil, nil //nolint: nilnil
+}
+
+// funcInfo is a function declared in the analyzed package.
+type funcInfo struct {
+	obj  *types.Func
+	decl *ast.FuncDecl
+}
+
+func collectFuncs(pass *analysis.Pass) []funcInfo {
+	var funcs []funcInfo
+
+	for _, file := range pass.Files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Body == nil {
+				continue
+			}
+
+			if obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func); ok {
+				funcs = append(funcs, funcInfo{obj: obj, decl: fn})
+			}
+		}
+	}
+
+	return funcs
+}
+
+// returnsWrapped returns the name of the function through which fn returns a wrapped error,
+// or an empty string if it does not.
+func returnsWrapped(pass *analysis.Pass, fn funcInfo) string {
+	sig, ok := fn.obj.Type().(*types.Signature)
+	if !ok {
+		return ""
+	}
+
+	results := sig.Results()
+	if results.Len() == 0 || !analysisutil.IsError(results.At(results.Len()-1).Type()) {
+		return ""
+	}
+
+	tainted := taintedVars(pass, fn.decl.Body)
+
+	var via string
+
+	ast.Inspect(fn.decl.Body, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(stmt.Results) == 0 || via != "" {
+				return true
+			}
+
+			via = wrappedBy(pass, tainted, stmt.Results[len(stmt.Results)-1])
+		}
+
+		return true
+	})
+
+	return via
+}
+
+// wrappedBy returns the name of the function through which the expression is wrapped,
+// or an empty string if it is not known to be wrapped.
+func wrappedBy(pass *analysis.Pass, tainted map[*types.Var]string, expr ast.Expr) string {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		fn := typeutil.StaticCallee(pass.TypesInfo, e)
+		if fn == nil {
+			return ""
+		}
+
+		if analysisutil.IsPkgFunc(fn, analysisutil.WerrPath) {
+			// Unwrap, Cause and the like return unwrapped errors.
+			if !strings.HasPrefix(fn.Name(), "Wrap") && fn.Name() != "PanicToError" {
+				return ""
+			}
+
+			return "werr." + fn.Name()
+		}
+
+		if pass.ImportObjectFact(fn.Origin(), new(wrapsFact)) {
+			return fn.Name()
+		}
+	case *ast.Ident:
+		if v, ok := pass.TypesInfo.Uses[e].(*types.Var); ok {
+			return tainted[v]
+		}
+	}
+
+	return ""
+}
+
+// taintedVars returns the local variables assigned from calls that return wrapped errors.
+// The analysis is flow-insensitive: a variable is tainted by any such assignment.
+func taintedVars(pass *analysis.Pass, body *ast.BlockStmt) map[*types.Var]string {
+	tainted := make(map[*types.Var]string)
+
+	taint := func(lhs ast.Expr, rhs ast.Expr) {
+		id, ok := ast.Unparen(lhs).(*ast.Ident)
+		if !ok {
+			return
+		}
+
+		v, ok := pass.TypesInfo.ObjectOf(id).(*types.Var)
+		if !ok || !analysisutil.IsError(v.Type()) {
+			return
+		}
+
+		if via := wrappedBy(pass, tainted, rhs); via != "" {
+			tainted[v] = via
+		}
+	}
+
+	assign := func(lhs []ast.Expr, rhs []ast.Expr) {
+		switch {
+		case len(lhs) == len(rhs):
+			for i := range lhs {
+				taint(lhs[i], rhs[i])
+			}
+		case len(rhs) == 1:
+			// v, err := f(): the error is the last result.
+			taint(lhs[len(lhs)-1], rhs[0])
+		}
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.AssignStmt:
+			assign(stmt.Lhs, stmt.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(stmt.Names))
+			for i, name := range stmt.Names {
+				lhs[i] = name
+			}
+
+			assign(lhs, stmt.Values)
+		}
+
+		return true
+	})
+
+	return tainted
+}
+
+// checker holds the state for the file being analyzed.
+type checker struct {
+	pass    *analysis.Pass
+	file    *ast.File
+	ignored map[int]bool
+}
+
+func (c *checker) checkBody(body *ast.BlockStmt) {
+	tainted := taintedVars(c.pass, body)
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		if node == nil || c.ignored[c.pass.Fset.Position(node.Pos()).Line] {
+			return true
+		}
+
+		switch n := node.(type) {
+		case *ast.BinaryExpr:
+			c.checkComparison(tainted, n)
+		case *ast.TypeSwitchStmt:
+			c.checkTypeSwitch(tainted, n)
+		case *ast.TypeAssertExpr:
+			c.checkTypeAssert(tainted, n)
+		}
+
+		return true
+	})
+}
+
+func (c *checker) checkComparison(tainted map[*types.Var]string, expr *ast.BinaryExpr) {
+	if expr.Op != token.EQL && expr.Op != token.NEQ {
+		return
+	}
+
+	wrapped, other := expr.X, expr.Y
+
+	via := wrappedBy(c.pass, tainted, wrapped)
+	if via == "" {
+		wrapped, other = expr.Y, expr.X
+		via = wrappedBy(c.pass, tainted, wrapped)
+	}
+
+	if via == "" || c.isNil(other) {
+		return
+	}
+
+	name, edits := analysisutil.Import(c.file, "errors")
+
+	call := name + ".Is(" + c.text(wrapped) + ", " + c.text(other) + ")"
+	if expr.Op == token.NEQ {
+		call = "!" + call
+	}
+
+	edits = append(edits, analysis.TextEdit{Pos: expr.Pos(), End: expr.End(), NewText: []byte(call)})
+
+	c.pass.Report(analysis.Diagnostic{
+		Pos:     expr.Pos(),
+		End:     expr.End(),
+		Message: "comparison of error wrapped by " + via + " with " + expr.Op.String() + ", use errors.Is",
+		SuggestedFixes: []analysis.SuggestedFix{{
+			Message:   "Use errors.Is",
+			TextEdits: edits,
+		}},
+	})
+}
+
+func (c *checker) checkTypeAssert(tainted map[*types.Var]string, expr *ast.TypeAssertExpr) {
+	if expr.Type == nil {
+		return
+	}
+
+	if via := wrappedBy(c.pass, tainted, expr.X); via != "" && !c.isInterface(expr.Type) && !c.isWerrError(expr.Type) {
+		c.pass.Reportf(expr.Pos(), "type assertion on error wrapped by %s, use errors.As", via)
+	}
+}
+
+func (c *checker) checkTypeSwitch(tainted map[*types.Var]string, stmt *ast.TypeSwitchStmt) {
+	var guard ast.Expr
+
+	switch s := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		guard = s.X
+	case *ast.AssignStmt:
+		guard = s.Rhs[0]
+	}
+
+	assert, ok := ast.Unparen(guard).(*ast.TypeAssertExpr)
+	if !ok {
+		return
+	}
+
+	via := wrappedBy(c.pass, tainted, assert.X)
+	if via == "" {
+		return
+	}
+
+	// Like type assertions, switches matching only interfaces and werr.Error are correct.
+	for _, clause := range stmt.Body.List {
+		for _, typ := range clause.(*ast.CaseClause).List { //nolint: forcetypeassert
+			if !c.isNil(typ) && !c.isInterface(typ) && !c.isWerrError(typ) {
+				c.pass.Reportf(stmt.Pos(), "type switch on error wrapped by %s, use errors.As", via)
+
+				return
+			}
+		}
+	}
+}
+
+func (c *checker) isNil(expr ast.Expr) bool {
+	return c.pass.TypesInfo.Types[expr].IsNil()
+}
+
+func (c *checker) isInterface(expr ast.Expr) bool {
+	return types.IsInterface(c.pass.TypesInfo.TypeOf(expr))
+}
+
+// isWerrError reports whether the expression is the werr.Error type, which is what werr itself
+// asserts to find its own layers.
+func (c *checker) isWerrError(expr ast.Expr) bool {
+	named, ok := c.pass.TypesInfo.TypeOf(expr).(*types.Named)
+	if !ok {
+		return false
+	}
+
+	obj := named.Obj()
+
+	return obj.Pkg() != nil && obj.Pkg().Path() == analysisutil.WerrPath && obj.Name() == "Error"
+}
+
+// text returns the source text of the expression.
+func (c *checker) text(expr ast.Expr) string {
+	tokFile := c.pass.Fset.File(expr.Pos())
+
+	content, err := c.pass.ReadFile(tokFile.Name())
+	if err != nil {
+		return types.ExprString(expr)
+	}
+
+	return string(content[tokFile.Offset(expr.Pos()):tokFile.Offset(expr.End())])
+}
@@ -0,0 +1,15 @@
+package errcompare_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/safeblock-dev/werr/werrlint/errcompare"
+)
+
+func TestAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), errcompare.Analyzer, "lib", "c")
+}
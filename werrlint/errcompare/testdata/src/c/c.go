@@ -0,0 +1,82 @@
+package c
+
+import (
+	"io"
+	"lib"
+
+	"github.com/safeblock-dev/werr"
+)
+
+func compare() {
+	err := lib.Find()
+	if err == lib.ErrNotFound { // want `comparison of error wrapped by Find with ==, use errors.Is`
+		return
+	}
+
+	if lib.ErrNotFound != err { // want `comparison of error wrapped by Find with !=, use errors.Is`
+		return
+	}
+
+	if err == nil {
+		return
+	}
+
+	if _, err := lib.FindValue(); err == lib.ErrNotFound { // want `comparison of error wrapped by FindValue with ==, use errors.Is`
+		return
+	}
+
+	if lib.FindVia() == io.EOF { // want `comparison of error wrapped by FindVia with ==, use errors.Is`
+		return
+	}
+
+	if werr.Wrap(io.EOF) == io.EOF { // want `comparison of error wrapped by werr.Wrap with ==, use errors.Is`
+		return
+	}
+
+	if werr.Cause(err) == lib.ErrNotFound {
+		return
+	}
+
+	if lib.Plain() == lib.ErrNotFound {
+		return
+	}
+
+	//werr:ignore
+	if err == lib.ErrNotFound {
+		return
+	}
+}
+
+func assert() {
+	err := lib.Find()
+
+	if _, ok := err.(*lib.NotFoundError); ok { // want `type assertion on error wrapped by Find, use errors.As`
+		return
+	}
+
+	if _, ok := err.(interface{ Unwrap() error }); ok {
+		return
+	}
+
+	switch err.(type) { // want `type switch on error wrapped by Find, use errors.As`
+	case *lib.NotFoundError:
+	}
+
+	switch e := err.(type) { // want `type switch on error wrapped by Find, use errors.As`
+	case *lib.NotFoundError:
+		_ = e
+	}
+	if _, ok := err.(werr.Error); ok {
+		return
+	}
+
+	switch err.(type) {
+	case werr.Error, interface{ Unwrap() error }:
+	case nil:
+	}
+
+	switch err.(type) { // want `type switch on error wrapped by Find, use errors.As`
+	case werr.Error:
+	case *lib.NotFoundError:
+	}
+}
@@ -0,0 +1,14 @@
+// Package werr is a stub of github.com/safeblock-dev/werr for analyzer tests.
+package werr
+
+type Error struct{}
+
+func (Error) Error() string { return "" }
+
+func Wrap(err error) error { return err }
+
+func Wrapf(err error, format string, a ...any) error { return err }
+
+func Wrapt[T any](val T, err error) (T, error) { return val, err }
+
+func Cause(err error) error { return err }
@@ -0,0 +1,31 @@
+package lib
+
+import (
+	"errors"
+
+	"github.com/safeblock-dev/werr"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type NotFoundError struct{}
+
+func (*NotFoundError) Error() string { return "not found" }
+
+func Find() error { // want Find:`wraps\(werr.Wrap\)`
+	return werr.Wrap(ErrNotFound)
+}
+
+func FindValue() (int, error) { // want FindValue:`wraps\(werr.Wrapt\)`
+	return werr.Wrapt(0, ErrNotFound)
+}
+
+func FindVia() error { // want FindVia:`wraps\(Find\)`
+	err := Find()
+
+	return err
+}
+
+func Plain() error {
+	return ErrNotFound
+}
@@ -0,0 +1,172 @@
+// Package analysisutil contains helpers shared by the werr analyzers.
+package analysisutil
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+// WerrPath is the import path of the werr package.
+const WerrPath = "github.com/safeblock-dev/werr"
+
+// IgnoreDirective is the comment that suppresses diagnostics on its own line and on the next one.
+const IgnoreDirective = "//werr:ignore"
+
+// Ignored reports the lines of the file suppressed with IgnoreDirective.
+func Ignored(fset *token.FileSet, file *ast.File) map[int]bool {
+	lines := make(map[int]bool)
+
+	for _, group := range file.Comments {
+		for _, c := range group.List {
+			if !strings.HasPrefix(c.Text, IgnoreDirective) {
+				continue
+			}
+
+			line := fset.Position(c.Slash).Line
+			lines[line] = true
+			lines[line+1] = true
+		}
+	}
+
+	return lines
+}
+
+// HasIgnoreDirective reports whether the comment group, e.g. a function doc, contains IgnoreDirective.
+func HasIgnoreDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	for _, c := range doc.List {
+		if strings.HasPrefix(c.Text, IgnoreDirective) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsPkgFunc reports whether the function belongs to the package with the given import path.
+func IsPkgFunc(fn *types.Func, path string) bool {
+	return fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == path
+}
+
+// IsError reports whether the type is the predeclared error interface.
+func IsError(typ types.Type) bool {
+	return typ != nil && types.Identical(typ, types.Universe.Lookup("error").Type())
+}
+
+// Import returns the name under which the file imports the package with the given path,
+// and the edits adding the import if the file does not import it yet.
+// The name of a new import is the last element of the path. The import is added to the
+// group of its kind in the first import declaration, see groupEdit.
+func Import(file *ast.File, path string) (string, []analysis.TextEdit) {
+	for _, spec := range file.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err != nil || p != path {
+			continue
+		}
+
+		if spec.Name != nil && spec.Name.Name != "_" && spec.Name.Name != "." {
+			return spec.Name.Name, nil
+		}
+
+		return path[strings.LastIndex(path, "/")+1:], nil
+	}
+
+	name := path[strings.LastIndex(path, "/")+1:]
+	quoted := strconv.Quote(path)
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			continue
+		}
+
+		if gen.Lparen.IsValid() {
+			return name, []analysis.TextEdit{groupEdit(gen, path)}
+		}
+
+		return name, []analysis.TextEdit{{
+			Pos:     gen.End(),
+			End:     gen.End(),
+			NewText: []byte("\nimport " + quoted),
+		}}
+	}
+
+	return name, []analysis.TextEdit{{
+		Pos:     file.Name.End(),
+		End:     file.Name.End(),
+		NewText: []byte("\n\nimport " + quoted),
+	}}
+}
+
+// groupEdit returns the edit adding the import to a parenthesized declaration, grouped like goimports:
+// a standard package after the first standard import, another package after the import sharing the
+// longest path prefix with it, or in a new group at the start or the end of the declaration.
+func groupEdit(gen *ast.GenDecl, path string) analysis.TextEdit {
+	quoted := strconv.Quote(path)
+
+	var (
+		after  *ast.ImportSpec
+		length = -1
+	)
+
+	for _, spec := range gen.Specs {
+		s, ok := spec.(*ast.ImportSpec)
+		if !ok {
+			continue
+		}
+
+		p, err := strconv.Unquote(s.Path.Value)
+		if err != nil || isStd(p) != isStd(path) {
+			continue
+		}
+
+		if isStd(path) {
+			after = s
+
+			break
+		}
+
+		if n := commonPrefix(p, path); n > length {
+			after, length = s, n
+		}
+	}
+
+	switch {
+	case after != nil:
+		// The import goes after the comment of the spec, which belongs to it.
+		end := after.End()
+		if after.Comment != nil {
+			end = after.Comment.End()
+		}
+
+		return analysis.TextEdit{Pos: end, End: end, NewText: []byte("\n\t" + quoted)}
+	case isStd(path):
+		return analysis.TextEdit{Pos: gen.Lparen + 1, End: gen.Lparen + 1, NewText: []byte("\n\t" + quoted + "\n")}
+	default:
+		return analysis.TextEdit{Pos: gen.Rparen, End: gen.Rparen, NewText: []byte("\n\t" + quoted + "\n")}
+	}
+}
+
+// isStd reports whether the import path is a standard package, without a dot in its first element.
+func isStd(path string) bool {
+	return !strings.Contains(strings.SplitN(path, "/", 2)[0], ".") //nolint: mnd
+}
+
+// commonPrefix returns the number of leading path elements shared by the import paths.
+func commonPrefix(a, b string) int {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+
+	n := 0
+	for n < len(as) && n < len(bs) && as[n] == bs[n] {
+		n++
+	}
+
+	return n
+}
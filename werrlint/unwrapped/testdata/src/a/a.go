@@ -0,0 +1,121 @@
+package a
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+)
+
+var ErrNotFound = errors.New("not found")
+
+type wrapper struct {
+	err error
+}
+
+func (w wrapper) Error() string { return w.err.Error() }
+
+func (w wrapper) Unwrap() error { return w.err }
+
+func load() error { return nil }
+
+func loadValue() (int, error) { return 0, nil }
+
+func loadValues() (int, string, error) { return 0, "", nil }
+
+func returnLocal() error {
+	if err := load(); err != nil {
+		return err // want `error returned without wrapping, use werr.Wrap`
+	}
+
+	return nil
+}
+
+func returnCall() error {
+	return load() // want `error returned without wrapping, use werr.Wrap`
+}
+
+func returnParam(err error) (int, error) {
+	return 0, err // want `error returned without wrapping, use werr.Wrap`
+}
+
+func returnTuple() (int, error) {
+	return loadValue() // want `error returned without wrapping, use werr.Wrapt`
+}
+
+func returnTriple() (int, string, error) {
+	return loadValues() // want `error returned without wrapping, use werr.Wrapt`
+}
+
+func returnSentinel() error {
+	return ErrNotFound // want `sentinel error ErrNotFound returned without wrapping, use werr.Wrap`
+}
+
+func returnAllowed() error {
+	return io.EOF
+}
+
+func walk(root string) {
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err // want `error returned without wrapping, use werr.Wrap`
+		}
+
+		if d.Name() == "vendor" {
+			return filepath.SkipDir
+		}
+
+		if d.Name() == "done" {
+			return fs.SkipAll
+		}
+
+		return nil
+	})
+}
+
+func returnCreated() error {
+	if true {
+		return errors.New("created")
+	}
+
+	if false {
+		return fmt.Errorf("created: %d", 1)
+	}
+
+	return wrapper{err: ErrNotFound}
+}
+
+func returnField(w wrapper) error {
+	return w.err
+}
+
+func returnClosure() func() error {
+	return func() error {
+		return load() // want `error returned without wrapping, use werr.Wrap`
+	}
+}
+
+func returnIgnored() error {
+	err := load()
+
+	//werr:ignore
+	return err
+}
+
+func returnIgnoredInline() error {
+	return load() //werr:ignore
+}
+
+// returnIgnoredFunc is skipped entirely.
+//
+//werr:ignore
+func returnIgnoredFunc() error {
+	return load()
+}
+
+func returnNamed() (err error) {
+	err = load()
+
+	return
+}
@@ -0,0 +1,15 @@
+package b
+
+import wr "github.com/safeblock-dev/werr"
+
+func load() (int, error) { return 0, nil }
+
+func wrapped() (int, error) {
+	return wr.Wrapt(load())
+}
+
+func unwrapped() error {
+	_, err := load()
+
+	return err // want `error returned without wrapping, use werr.Wrap`
+}
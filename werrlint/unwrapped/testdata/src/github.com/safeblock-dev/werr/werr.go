@@ -0,0 +1,8 @@
+// Package werr is a stub of github.com/safeblock-dev/werr for analyzer tests.
+package werr
+
+func Wrap(err error) error { return err }
+
+func Wrapf(err error, format string, a ...any) error { return err }
+
+func Wrapt[T any](val T, err error) (T, error) { return val, err }
@@ -0,0 +1,279 @@
+// Package unwrapped defines an Analyzer that reports errors returned
+// across function boundaries without werr wrapping.
+package unwrapped
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/safeblock-dev/werr/werrlint/internal/analysisutil"
+)
+
+// Doc is the documentation of the analyzer.
+const Doc = `report errors returned without werr wrapping
+
+A function that returns an error received from another function, or a
+sentinel error, without werr.Wrap loses a hop in the werr trace:
+
+	if err := load(); err != nil {
+		return err // want: return werr.Wrap(err)
+	}
+
+Calls returning a value and an error are fixed with werr.Wrapt:
+
+	return werr.Wrapt(load())
+
+Errors created in place (errors.New, fmt.Errorf, composite literals) and
+errors stored in struct fields are not reported. Unwrap and Cause methods
+are skipped. Sentinels that must be returned as is, such as io.EOF or
+filepath.SkipDir, are listed with the -allow flag. A diagnostic is
+suppressed by a //werr:ignore comment on the return statement, on the
+line above it, or in the doc comment of the function.`
+
+// Analyzer reports errors returned without werr wrapping.
+var Analyzer = &analysis.Analyzer{ //nolint: gochecknoglobals
+	Name: "unwrapped",
+	Doc:  Doc,
+	URL:  "https://pkg.go.dev/github.com/safeblock-dev/werr/werrlint/unwrapped",
+	Run:  run,
+}
+
+// allow lists by default the standard sentinels that callers compare with ==,
+// where a wrapped error breaks the caller, e.g. filepath.SkipDir returned to filepath.WalkDir.
+var allow = strings.Join([]string{ //nolint: gochecknoglobals
+	"bufio.ErrFinalToken",
+	"database/sql/driver.ErrRemoveArgument",
+	"database/sql/driver.ErrSkip",
+	"io.EOF",
+	"io/fs.SkipAll",
+	"io/fs.SkipDir",
+	"net/http.ErrUseLastResponse",
+	"path/filepath.SkipAll",
+	"path/filepath.SkipDir",
+}, ",")
+
+func init() { //nolint: gochecknoinits
+	Analyzer.Flags.StringVar(&allow, "allow", allow,
+		"comma-separated list of sentinel errors returned without wrapping, e.g. io.EOF,database/sql.ErrNoRows")
+}
+
+// creators are the functions that create a new error rather than pass one through.
+var creators = map[string]bool{ //nolint: gochecknoglobals
+	"errors.New":  true,
+	"errors.Join": true,
+	"fmt.Errorf":  true,
+}
+
+// checker holds the state for the file being analyzed.
+type checker struct {
+	pass    *analysis.Pass
+	file    *ast.File
+	ignored map[int]bool
+	allowed map[string]bool
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	// werr itself implements wrapping.
+	if pass.Pkg.Path() == analysisutil.WerrPath {
+		return nil, nil //nolint: nilnil
+	}
+
+	allowed := make(map[string]bool)
+
+	for _, name := range strings.Split(allow, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			allowed[name] = true
+		}
+	}
+
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+
+		c := &checker{
+			pass:    pass,
+			file:    file,
+			ignored: analysisutil.Ignored(pass.Fset, file),
+			allowed: allowed,
+		}
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			switch fn := node.(type) {
+			case *ast.FuncDecl:
+				c.checkFuncDecl(fn)
+			case *ast.FuncLit:
+				if sig, ok := pass.TypesInfo.TypeOf(fn).(*types.Signature); ok {
+					c.checkBody(sig, fn.Body)
+				}
+			}
+
+			return true
+		})
+	}
+
+	return nil, nil //nolint: nilnil
+}
+
+func (c *checker) checkFuncDecl(fn *ast.FuncDecl) {
+	if fn.Body == nil || analysisutil.HasIgnoreDirective(fn.Doc) {
+		return
+	}
+
+	obj, ok := c.pass.TypesInfo.Defs[fn.Name].(*types.Func)
+	if !ok {
+		return
+	}
+
+	sig, ok := obj.Type().(*types.Signature)
+	if !ok {
+		return
+	}
+
+	// Unwrap and Cause methods return the inner error by contract.
+	if sig.Recv() != nil && (fn.Name.Name == "Unwrap" || fn.Name.Name == "Cause") {
+		return
+	}
+
+	c.checkBody(sig, fn.Body)
+}
+
+// checkBody checks the return statements of a function body, excluding nested function literals.
+func (c *checker) checkBody(sig *types.Signature, body *ast.BlockStmt) {
+	results := sig.Results()
+	if results.Len() == 0 || !analysisutil.IsError(results.At(results.Len()-1).Type()) {
+		return
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch stmt := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if !c.ignored[c.pass.Fset.Position(stmt.Pos()).Line] {
+				c.checkReturn(results.Len(), stmt)
+			}
+		}
+
+		return true
+	})
+}
+
+func (c *checker) checkReturn(n int, stmt *ast.ReturnStmt) {
+	switch {
+	case len(stmt.Results) == 0:
+		// Naked return of named results.
+	case len(stmt.Results) == 1 && n > 1:
+		// return f(), where f returns all the results.
+		c.checkTuple(n, stmt.Results[0])
+	default:
+		c.checkError(stmt.Results[len(stmt.Results)-1])
+	}
+}
+
+// checkTuple checks a call returning several results, the last of which is an error.
+func (c *checker) checkTuple(n int, expr ast.Expr) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || c.isWerr(call) {
+		return
+	}
+
+	diag := analysis.Diagnostic{
+		Pos:     expr.Pos(),
+		End:     expr.End(),
+		Message: "error returned without wrapping, use werr.Wrapt",
+	}
+
+	// Wrapt accepts a single value along with the error.
+	if n == 2 { //nolint: mnd
+		diag.SuggestedFixes = c.fix("Wrap with werr.Wrapt", "Wrapt", expr)
+	}
+
+	c.pass.Report(diag)
+}
+
+// checkError checks an expression of the error result.
+func (c *checker) checkError(expr ast.Expr) {
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		v, ok := c.pass.TypesInfo.Uses[e].(*types.Var)
+		if !ok || !analysisutil.IsError(v.Type()) {
+			return
+		}
+
+		if isPackageLevel(v) {
+			c.checkSentinel(expr, v)
+
+			return
+		}
+	case *ast.SelectorExpr:
+		// Only qualified identifiers (pkg.ErrX) are sentinels, fields are skipped.
+		v, ok := c.pass.TypesInfo.Uses[e.Sel].(*types.Var)
+		if !ok || !isPackageLevel(v) || !analysisutil.IsError(v.Type()) {
+			return
+		}
+
+		c.checkSentinel(expr, v)
+
+		return
+	case *ast.CallExpr:
+		if c.pass.TypesInfo.Types[e.Fun].IsType() || c.isWerr(e) || c.isCreator(e) {
+			return
+		}
+	default:
+		return
+	}
+
+	c.pass.Report(analysis.Diagnostic{
+		Pos:            expr.Pos(),
+		End:            expr.End(),
+		Message:        "error returned without wrapping, use werr.Wrap",
+		SuggestedFixes: c.fix("Wrap with werr.Wrap", "Wrap", expr),
+	})
+}
+
+func (c *checker) checkSentinel(expr ast.Expr, v *types.Var) {
+	if c.allowed[v.Pkg().Path()+"."+v.Name()] {
+		return
+	}
+
+	c.pass.Report(analysis.Diagnostic{
+		Pos:            expr.Pos(),
+		End:            expr.End(),
+		Message:        "sentinel error " + v.Name() + " returned without wrapping, use werr.Wrap",
+		SuggestedFixes: c.fix("Wrap with werr.Wrap", "Wrap", expr),
+	})
+}
+
+// fix returns a fix wrapping the expression in a call to the given werr function.
+func (c *checker) fix(message, fn string, expr ast.Expr) []analysis.SuggestedFix {
+	name, edits := analysisutil.Import(c.file, analysisutil.WerrPath)
+
+	edits = append(edits,
+		analysis.TextEdit{Pos: expr.Pos(), End: expr.Pos(), NewText: []byte(name + "." + fn + "(")},
+		analysis.TextEdit{Pos: expr.End(), End: expr.End(), NewText: []byte(")")},
+	)
+
+	return []analysis.SuggestedFix{{Message: message, TextEdits: edits}}
+}
+
+// isWerr reports whether the call is a call to a werr function.
+func (c *checker) isWerr(call *ast.CallExpr) bool {
+	return analysisutil.IsPkgFunc(typeutil.StaticCallee(c.pass.TypesInfo, call), analysisutil.WerrPath)
+}
+
+// isCreator reports whether the call creates a new error.
+func (c *checker) isCreator(call *ast.CallExpr) bool {
+	fn := typeutil.StaticCallee(c.pass.TypesInfo, call)
+
+	return fn != nil && fn.Pkg() != nil && creators[fn.Pkg().Path()+"."+fn.Name()]
+}
+
+// isPackageLevel reports whether the variable is declared at package level.
+func isPackageLevel(v *types.Var) bool {
+	return v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
@@ -0,0 +1,15 @@
+package unwrapped_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/safeblock-dev/werr/werrlint/unwrapped"
+)
+
+func TestAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), unwrapped.Analyzer, "a", "b")
+}
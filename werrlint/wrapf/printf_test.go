@@ -0,0 +1,65 @@
+package wrapf_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+	"golang.org/x/tools/go/analysis/passes/printf"
+)
+
+// TestPrintfWrapper checks that go vet's printf checker infers werr.Wrapf as a printf wrapper
+// from the sources of the werr package, so that callers keep format checking.
+func TestPrintfWrapper(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pkg := filepath.Join(dir, "src", "github.com", "safeblock-dev", "werr")
+	if err := os.MkdirAll(pkg, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "*.go"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("werr sources not found: %v", err)
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filepath.Join(pkg, filepath.Base(file)), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	caller := filepath.Join(dir, "src", "caller")
+	if err := os.MkdirAll(caller, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(caller, "caller.go"), []byte(`package caller
+
+import "github.com/safeblock-dev/werr"
+
+func load(err error, id int) error {
+	_ = werr.Wrapf(err, "loading %d", id)
+	_ = werr.Wrapf(err, "loading %d", werr.Secret(id))
+	_ = werr.Wrapf(err, "loaded 100%") // want `+"`Wrapf format % is missing verb at end of string`"+`
+
+	return werr.Wrapf(err, "loading %d", "id") // want `+"`Wrapf format %d has arg \"id\" of wrong type string`"+`
+}
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	analysistest.Run(t, dir, printf.Analyzer, "caller")
+}
@@ -0,0 +1,52 @@
+package a
+
+import (
+	"errors"
+	"lib"
+
+	"github.com/safeblock-dev/werr"
+)
+
+var errFailed = errors.New("failed")
+
+func verbs(name string, n int) {
+	_ = werr.Wrapf(errFailed, "load %s: %d", name, n)
+	_ = werr.Wrapf(errFailed, "load %w", errFailed)    // want `werr.Wrapf does not support error-wrapping directive %w, the wrapped error is its first argument`
+	_ = werr.Wrapf(errFailed, "load %[1]w", errFailed) // want `werr.Wrapf does not support error-wrapping directive %w`
+	_ = werr.Wrapf(errFailed, "100%% wrapped %s", name)
+	_ = werr.Wrapf(errFailed, "100%")
+}
+
+func args(name string, n int) {
+	_ = werr.Wrapf(errFailed, werr.ArgsFormat, werr.Args(name, n))
+	_ = werr.Wrapf(errFailed, "args=%+v", werr.Args(name, n))
+	_ = werr.Wrapf(errFailed, "load %v", werr.Args(name, n)) // want `werr.Args passed to werr.Wrapf with a format other than werr.ArgsFormat`
+}
+
+func nonConstant(msg string) {
+	_ = werr.Wrapf(errFailed, msg, werr.Args(1))
+}
+
+func wrappers(name string) {
+	_ = lib.Wrapf(errFailed, "load %w", name) // want `Wrapf does not support error-wrapping directive %w`
+	_ = lib.Failf("load %v", werr.Args(name)) // want `werr.Args passed to Failf with a format other than werr.ArgsFormat`
+	lib.Log("load %w", name)
+}
+
+func local(name string) {
+	wrapf := func(format string, args ...any) error { return werr.Wrapf(errFailed, format, args...) }
+	_ = wrapf("load %w", name)
+
+	//werr:ignore
+	_ = werr.Wrapf(errFailed, "load %w", name)
+	_ = werr.Wrapf(errFailed, "load %w", name) //werr:ignore
+}
+
+// localf forwards to werr.Wrapf in the same package.
+func localf(format string, args ...any) error { // want localf:"wrapf\\(0\\)"
+	return werr.Wrapf(errFailed, format, args...)
+}
+
+func useLocal(name string) {
+	_ = localf("load %w", name) // want `localf does not support error-wrapping directive %w`
+}
@@ -0,0 +1,12 @@
+// Package werr is a stub of github.com/safeblock-dev/werr for analyzer tests.
+package werr
+
+const ArgsFormat = "args=%+v"
+
+func Wrap(err error) error { return err }
+
+func Wrapf(err error, format string, a ...any) error { return err }
+
+func Args(args ...any) []any { return args }
+
+func Secret(v any) any { return v }
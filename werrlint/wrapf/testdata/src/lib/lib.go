@@ -0,0 +1,16 @@
+package lib
+
+import "github.com/safeblock-dev/werr"
+
+// Wrapf forwards to werr.Wrapf.
+func Wrapf(err error, format string, args ...any) error { // want Wrapf:"wrapf\\(1\\)"
+	return werr.Wrapf(err, format, args...)
+}
+
+// Failf forwards to a forwarding function.
+func Failf(format string, args ...any) error { // want Failf:"wrapf\\(0\\)"
+	return Wrapf(nil, format, args...)
+}
+
+// Log has a format but does not forward it.
+func Log(format string, args ...any) {}
@@ -0,0 +1,290 @@
+// Package wrapf defines an Analyzer that checks werr-specific mistakes in the format
+// strings of werr.Wrapf and of functions forwarding their arguments to it.
+package wrapf
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+
+	"github.com/safeblock-dev/werr/werrlint/internal/analysisutil"
+)
+
+// Doc is the documentation of the analyzer.
+const Doc = `check werr-specific mistakes in format strings of werr.Wrapf
+
+werr.Wrapf(err, format, a...) formats its message like fmt.Sprintf and is
+recognized as a printf wrapper by go vet, which checks its verbs and
+arguments. The analyzer reports the mistakes vet does not know about:
+
+  - the %w verb, since the wrapped error is the first argument of Wrapf;
+  - werr.Args passed with a format other than werr.ArgsFormat.
+
+Functions with a format string parameter followed by "args ...any" that
+forward both to werr.Wrapf, or to another such function, are checked too,
+including across packages. A diagnostic is suppressed by a //werr:ignore
+comment on the same line or on the line above it.`
+
+// argsFormat is the value of werr.ArgsFormat.
+const argsFormat = "args=%+v"
+
+// Analyzer checks format strings of werr.Wrapf.
+var Analyzer = &analysis.Analyzer{ //nolint: gochecknoglobals
+	Name:      "wrapf",
+	Doc:       Doc,
+	URL:       "https://pkg.go.dev/github.com/safeblock-dev/werr/werrlint/wrapf",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(wrapperFact)},
+}
+
+// wrapperFact marks a function that forwards its format and arguments to werr.Wrapf.
+type wrapperFact struct {
+	Format int // Format is the index of the format parameter.
+}
+
+// AFact implements analysis.Fact.
+func (*wrapperFact) AFact() {}
+
+func (f *wrapperFact) String() string {
+	return "wrapf(" + strconv.Itoa(f.Format) + ")"
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	findWrappers(pass)
+
+	for _, file := range pass.Files {
+		if ast.IsGenerated(file) {
+			continue
+		}
+
+		ignored := analysisutil.Ignored(pass.Fset, file)
+
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok || ignored[pass.Fset.Position(call.Pos()).Line] {
+				return true
+			}
+
+			if fn, format := formatFunc(pass, call); fn != nil {
+				c := &checker{pass: pass, call: call, name: funcName(fn), format: format}
+				c.check()
+			}
+
+			return true
+		})
+	}
+
+	return nil, nil //nolint: nilnil
+}
+
+// formatFunc returns the called function and the index of its format parameter,
+// if the call is a call to werr.Wrapf or to a function forwarding to it.
+func formatFunc(pass *analysis.Pass, call *ast.CallExpr) (*types.Func, int) {
+	fn := typeutil.StaticCallee(pass.TypesInfo, call)
+	if fn == nil {
+		return nil, 0
+	}
+
+	if analysisutil.IsPkgFunc(fn, analysisutil.WerrPath) {
+		if idx := formatParam(fn); idx >= 0 {
+			return fn, idx
+		}
+
+		return nil, 0
+	}
+
+	var fact wrapperFact
+	if pass.ImportObjectFact(fn.Origin(), &fact) {
+		return fn, fact.Format
+	}
+
+	return nil, 0
+}
+
+// formatParam returns the index of a "format string" parameter directly followed
+// by a final "...any" parameter, or -1.
+func formatParam(fn *types.Func) int {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || !sig.Variadic() || sig.Params().Len() < 2 { //nolint: mnd
+		return -1
+	}
+
+	params := sig.Params()
+	idx := params.Len() - 2 //nolint: mnd
+
+	slice, ok := params.At(idx + 1).Type().(*types.Slice)
+	if !ok || !types.IsInterface(slice.Elem()) {
+		return -1
+	}
+
+	if basic, ok := params.At(idx).Type().(*types.Basic); !ok || basic.Kind() != types.String {
+		return -1
+	}
+
+	return idx
+}
+
+// findWrappers exports facts for the functions of the package forwarding to werr.Wrapf.
+func findWrappers(pass *analysis.Pass) {
+	for changed := true; changed; {
+		changed = false
+
+		for _, file := range pass.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Body == nil {
+					continue
+				}
+
+				obj, ok := pass.TypesInfo.Defs[fn.Name].(*types.Func)
+				if !ok || pass.ImportObjectFact(obj, new(wrapperFact)) {
+					continue
+				}
+
+				if idx := formatParam(obj); idx >= 0 && forwards(pass, obj, fn.Body, idx) {
+					pass.ExportObjectFact(obj, &wrapperFact{Format: idx})
+
+					changed = true
+				}
+			}
+		}
+	}
+}
+
+// forwards reports whether the body passes the format parameter and the variadic arguments
+// of fn to a checked function.
+func forwards(pass *analysis.Pass, fn *types.Func, body *ast.BlockStmt, idx int) bool {
+	params := fn.Type().(*types.Signature).Params() //nolint: forcetypeassert
+	format, args := params.At(idx), params.At(idx+1)
+
+	var found bool
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || found || !call.Ellipsis.IsValid() {
+			return !found
+		}
+
+		callee, calleeIdx := formatFunc(pass, call)
+		if callee == nil || len(call.Args) != calleeIdx+2 {
+			return true
+		}
+
+		found = isVar(pass, call.Args[calleeIdx], format) && isVar(pass, call.Args[calleeIdx+1], args)
+
+		return !found
+	})
+
+	return found
+}
+
+func isVar(pass *analysis.Pass, expr ast.Expr, v *types.Var) bool {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+
+	return ok && pass.TypesInfo.Uses[id] == v
+}
+
+func funcName(fn *types.Func) string {
+	if fn.Pkg() != nil && fn.Pkg().Path() == analysisutil.WerrPath {
+		return "werr." + fn.Name()
+	}
+
+	return fn.Name()
+}
+
+// checker checks a single call.
+type checker struct {
+	pass   *analysis.Pass
+	call   *ast.CallExpr
+	name   string
+	format int
+}
+
+func (c *checker) check() {
+	if len(c.call.Args) <= c.format {
+		return
+	}
+
+	formatExpr := c.call.Args[c.format]
+
+	// Non-constant formats are reported by vet.
+	tv := c.pass.TypesInfo.Types[formatExpr]
+	if tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+
+	format := constant.StringVal(tv.Value)
+
+	c.checkArgs(formatExpr, format, c.call.Args[c.format+1:])
+
+	if hasWrapVerb(format) {
+		c.pass.Reportf(c.call.Pos(), "%s does not support error-wrapping directive %%w, the wrapped error is its first argument", c.name)
+	}
+}
+
+// checkArgs reports werr.Args passed with a format other than werr.ArgsFormat.
+func (c *checker) checkArgs(formatExpr ast.Expr, format string, args []ast.Expr) {
+	if c.isArgsFormat(formatExpr, format) {
+		return
+	}
+
+	for _, arg := range args {
+		call, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+
+		fn := typeutil.StaticCallee(c.pass.TypesInfo, call)
+		if analysisutil.IsPkgFunc(fn, analysisutil.WerrPath) && fn.Name() == "Args" {
+			c.pass.Reportf(call.Pos(), "werr.Args passed to %s with a format other than werr.ArgsFormat", c.name)
+		}
+	}
+}
+
+func (c *checker) isArgsFormat(expr ast.Expr, format string) bool {
+	var id *ast.Ident
+
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = e
+	case *ast.SelectorExpr:
+		id = e.Sel
+	}
+
+	if id != nil {
+		if obj, ok := c.pass.TypesInfo.Uses[id].(*types.Const); ok && obj.Pkg() != nil &&
+			obj.Pkg().Path() == analysisutil.WerrPath && obj.Name() == "ArgsFormat" {
+			return true
+		}
+	}
+
+	return format == argsFormat
+}
+
+// hasWrapVerb reports whether the format uses the %w verb.
+func hasWrapVerb(format string) bool {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		// Skip the flags, the width, the precision and the argument indexes.
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.*[]", format[j]) >= 0 {
+			j++
+		}
+
+		if j < len(format) && format[j] == 'w' {
+			return true
+		}
+
+		i = j
+	}
+
+	return false
+}
@@ -0,0 +1,15 @@
+package wrapf_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/safeblock-dev/werr/werrlint/wrapf"
+)
+
+func TestAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	analysistest.Run(t, analysistest.TestData(), wrapf.Analyzer, "lib", "a")
+}
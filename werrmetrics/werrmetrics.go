@@ -0,0 +1,232 @@
+// Package werrmetrics counts wrapped errors by wrap site without a metrics client.
+//
+// Counters are keyed by the package and function of the wrap site and by an error code,
+// fed by werr.OnWrap, and published through expvar and an http.Handler emitting the
+// Prometheus text exposition format:
+//
+//	m := werrmetrics.New()
+//	werr.OnWrap(m.Hook)
+//	m.Publish("werr")
+//	http.Handle("/metrics/werr", m)
+//
+// The number of series is bounded: once the limit is reached, new series are
+// counted in a single series whose labels are all Other.
+package werrmetrics
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// Other is the value of every label of the series counting errors beyond the series limit.
+const Other = "_other"
+
+// DefaultMaxSeries is the default limit of the number of series.
+const DefaultMaxSeries = 1000
+
+// MetricName is the name of the counter in the Prometheus exposition.
+const MetricName = "werr_wrapped_errors_total"
+
+// CodeFunc returns the code of an error, a label value from a small set.
+type CodeFunc func(err error) string
+
+// Option configures Metrics.
+type Option func(*Metrics)
+
+// WithMaxSeries sets the limit of the number of series, including the overflow series,
+// DefaultMaxSeries by default.
+func WithMaxSeries(n int) Option {
+	return func(m *Metrics) {
+		m.maxSeries = n
+	}
+}
+
+// WithCode sets the function returning the code of an error, Code by default.
+func WithCode(fn CodeFunc) Option {
+	return func(m *Metrics) {
+		m.code = fn
+	}
+}
+
+// Code is the default code function: it returns the result of the Code method
+// of the first error of the chain that has one, or an empty string.
+func Code(err error) string {
+	var coder interface{ Code() string }
+	if errors.As(err, &coder) {
+		return coder.Code()
+	}
+
+	return ""
+}
+
+// series are the labels of a counter.
+type series struct {
+	pkg, fn, code string
+}
+
+// Metrics counts wrapped errors by wrap site and code. It is safe for concurrent use.
+type Metrics struct {
+	maxSeries int
+	code      CodeFunc
+
+	mu     sync.Mutex
+	counts map[series]uint64
+}
+
+// New returns empty metrics.
+func New(opts ...Option) *Metrics {
+	m := &Metrics{
+		maxSeries: DefaultMaxSeries,
+		code:      Code,
+		counts:    make(map[series]uint64),
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Hook counts the wrapped error under its wrap site; it can be registered with werr.OnWrap and werr.OnPanic.
+func (m *Metrics) Hook(frame werr.Frame, err error) {
+	m.inc(series{pkg: frame.Package(), fn: frame.Func(), code: m.code(err)})
+}
+
+// Add counts err under its outermost wrap site. Errors not wrapped by werr are counted
+// with empty package and function labels. Nil errors are ignored.
+func (m *Metrics) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	var s series
+
+	var e werr.Error
+	if errors.As(err, &e) {
+		s.pkg, s.fn = e.Frame().Package(), e.Frame().Func()
+	}
+
+	s.code = m.code(err)
+	m.inc(s)
+}
+
+// inc increments the counter of the series, or the overflow series beyond the limit.
+// The last slot of the limit is reserved for the overflow series.
+func (m *Metrics) inc(s series) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.counts[s]; !ok && len(m.counts) >= m.maxSeries-1 {
+		s = series{pkg: Other, fn: Other, code: Other}
+	}
+
+	m.counts[s]++
+}
+
+// Reset drops all counters.
+func (m *Metrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts = make(map[series]uint64)
+}
+
+// Count is a counter of a series.
+type Count struct {
+	Package  string `json:"package"`  // Package is the import path of the package of the wrap site.
+	Function string `json:"function"` // Function is the function of the wrap site within the package.
+	Code     string `json:"code"`     // Code is the code of the error.
+	Count    uint64 `json:"count"`    // Count is the number of wrapped errors.
+}
+
+// Counts returns the counters sorted by package, function and code.
+func (m *Metrics) Counts() []Count {
+	m.mu.Lock()
+
+	counts := make([]Count, 0, len(m.counts))
+	for s, n := range m.counts {
+		counts = append(counts, Count{Package: s.pkg, Function: s.fn, Code: s.code, Count: n})
+	}
+
+	m.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+
+		if a.Function != b.Function {
+			return a.Function < b.Function
+		}
+
+		return a.Code < b.Code
+	})
+
+	return counts
+}
+
+// String returns the counters as a JSON array, implementing expvar.Var.
+func (m *Metrics) String() string {
+	b, _ := json.Marshal(m.Counts())
+
+	return string(b)
+}
+
+// Publish publishes the counters as an expvar variable, served by expvar at /debug/vars.
+// Like expvar.Publish, it panics if the name is already registered.
+func (m *Metrics) Publish(name string) {
+	expvar.Publish(name, m)
+}
+
+// ServeHTTP writes the counters in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(m.AppendText(nil))
+}
+
+// AppendText appends the counters in the Prometheus text exposition format.
+func (m *Metrics) AppendText(dst []byte) []byte {
+	dst = append(dst, "# HELP "+MetricName+" Errors wrapped by werr, by wrap site and code.\n"...)
+	dst = append(dst, "# TYPE "+MetricName+" counter\n"...)
+
+	for _, c := range m.Counts() {
+		dst = append(dst, MetricName+`{package="`...)
+		dst = appendLabelValue(dst, c.Package)
+		dst = append(dst, `",function="`...)
+		dst = appendLabelValue(dst, c.Function)
+		dst = append(dst, `",code="`...)
+		dst = appendLabelValue(dst, c.Code)
+		dst = append(dst, "\"} "...)
+		dst = strconv.AppendUint(dst, c.Count, 10)
+		dst = append(dst, '\n')
+	}
+
+	return dst
+}
+
+// appendLabelValue appends a label value escaped as required by the text exposition format.
+func appendLabelValue(dst []byte, v string) []byte {
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; c {
+		case '\\':
+			dst = append(dst, `\\`...)
+		case '"':
+			dst = append(dst, `\"`...)
+		case '\n':
+			dst = append(dst, `\n`...)
+		default:
+			dst = append(dst, c)
+		}
+	}
+
+	return dst
+}
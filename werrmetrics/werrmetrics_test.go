@@ -0,0 +1,128 @@
+package werrmetrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+	"github.com/safeblock-dev/werr/werrmetrics"
+)
+
+type codeError string
+
+func (e codeError) Error() string { return "code " + string(e) }
+
+func (e codeError) Code() string { return string(e) }
+
+var errNotFound = codeError("not_found")
+
+func load(err error) error {
+	return werr.Wrap(err)
+}
+
+type store struct{}
+
+func (*store) save(err error) error {
+	return werr.Wrapf(err, "saving")
+}
+
+// nolint: paralleltest
+func TestMetrics_Hook(t *testing.T) {
+	m := werrmetrics.New()
+	defer werr.OnWrap(m.Hook)()
+
+	_ = load(errNotFound)
+	_ = load(errNotFound)
+	_ = (&store{}).save(load(errors.New("boom")))
+
+	require.Equal(t, []werrmetrics.Count{
+		{Package: "github.com/safeblock-dev/werr/werrmetrics_test", Function: "(*store).save", Code: "", Count: 1},
+		{Package: "github.com/safeblock-dev/werr/werrmetrics_test", Function: "load", Code: "", Count: 1},
+		{Package: "github.com/safeblock-dev/werr/werrmetrics_test", Function: "load", Code: "not_found", Count: 2},
+	}, m.Counts())
+}
+
+func TestMetrics_Add(t *testing.T) {
+	t.Parallel()
+
+	t.Run("by outermost wrap site", func(t *testing.T) {
+		t.Parallel()
+
+		m := werrmetrics.New()
+		m.Add((&store{}).save(load(errNotFound)))
+		m.Add(errors.New("plain"))
+		m.Add(nil)
+
+		require.Equal(t, []werrmetrics.Count{
+			{Package: "", Function: "", Code: "", Count: 1},
+			{Package: "github.com/safeblock-dev/werr/werrmetrics_test", Function: "(*store).save", Code: "not_found", Count: 1},
+		}, m.Counts())
+	})
+
+	t.Run("with bounded cardinality", func(t *testing.T) {
+		t.Parallel()
+
+		m := werrmetrics.New(werrmetrics.WithMaxSeries(3), werrmetrics.WithCode(func(err error) string {
+			return err.(werr.Error).Cause().Error() //nolint: errorlint, forcetypeassert
+		}))
+
+		for _, code := range []string{"a", "b", "c", "d", "a"} {
+			m.Add(load(errors.New(code)))
+		}
+
+		require.Equal(t, []werrmetrics.Count{
+			{Package: werrmetrics.Other, Function: werrmetrics.Other, Code: werrmetrics.Other, Count: 2},
+			{Package: "github.com/safeblock-dev/werr/werrmetrics_test", Function: "load", Code: "a", Count: 2},
+			{Package: "github.com/safeblock-dev/werr/werrmetrics_test", Function: "load", Code: "b", Count: 1},
+		}, m.Counts())
+	})
+
+	t.Run("when reset", func(t *testing.T) {
+		t.Parallel()
+
+		m := werrmetrics.New()
+		m.Add(load(errNotFound))
+		m.Reset()
+
+		require.Empty(t, m.Counts())
+	})
+}
+
+func TestMetrics_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	m := werrmetrics.New(werrmetrics.WithCode(func(error) string { return "a\"b\\c\nd" }))
+	m.Add(load(errNotFound))
+	m.Add(load(errNotFound))
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	require.Equal(t, `# HELP werr_wrapped_errors_total Errors wrapped by werr, by wrap site and code.
+# TYPE werr_wrapped_errors_total counter
+werr_wrapped_errors_total{package="github.com/safeblock-dev/werr/werrmetrics_test",function="load",code="a\"b\\c\nd"} 2
+`, rec.Body.String())
+}
+
+func TestMetrics_Publish(t *testing.T) {
+	t.Parallel()
+
+	m := werrmetrics.New()
+	m.Add(load(errNotFound))
+	// expvar names cannot be published twice, e.g. with -count=2.
+	name := "werrmetrics_test_" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	m.Publish(name)
+
+	var counts []werrmetrics.Count
+	require.NoError(t, json.Unmarshal([]byte(expvar.Get(name).String()), &counts))
+	require.Equal(t, m.Counts(), counts)
+}
@@ -0,0 +1,177 @@
+// Package werrotel records werr errors on OpenTelemetry spans.
+//
+// The exception event follows the OpenTelemetry semantic conventions: its
+// exception.stacktrace attribute is built from the wrap sites of the error chain,
+// which are the frames Go errors otherwise lose, and the fields recorded with
+// werr.WrapArgs or werr.WrapCtx are added as "werr.<key>" attributes.
+package werrotel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// FingerprintKey is the attribute holding werr.Fingerprint of the recorded error.
+const FingerprintKey = attribute.Key("werr.fingerprint")
+
+// Classifier returns the span status code for an error.
+// codes.Unset leaves the span status untouched.
+type Classifier func(err error) codes.Code
+
+// Option configures Record and RecordError.
+type Option func(*config)
+
+type config struct {
+	classify Classifier
+	attrs    []attribute.KeyValue
+}
+
+// WithClassifier sets the function selecting the span status of an error.
+// The default is Classify.
+func WithClassifier(fn Classifier) Option {
+	return func(c *config) {
+		c.classify = fn
+	}
+}
+
+// WithAttributes adds attributes to the exception event.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// Classify is the default classifier: context cancellation, usually caused by the client,
+// leaves the span status unset, and every other error sets it to codes.Error.
+func Classify(err error) codes.Code {
+	if errors.Is(err, context.Canceled) {
+		return codes.Unset
+	}
+
+	return codes.Error
+}
+
+// Record records err on the span active in ctx, see RecordError.
+func Record(ctx context.Context, err error, opts ...Option) {
+	RecordError(trace.SpanFromContext(ctx), err, opts...)
+}
+
+// RecordError adds an exception event describing err to the span and sets the span status
+// according to the classifier. Values marked with werr.Secret are redacted.
+// If the error is nil or the span is not recording, the function does nothing.
+func RecordError(span trace.Span, err error, opts ...Option) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	cfg := config{classify: Classify}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	message := Message(err)
+
+	attrs := make([]attribute.KeyValue, 0, 4+len(cfg.attrs))
+	attrs = append(attrs,
+		semconv.ExceptionType(Type(err)),
+		semconv.ExceptionMessage(message),
+		semconv.ExceptionStacktrace(Stacktrace(err)),
+		FingerprintKey.String(werr.Fingerprint(err)),
+	)
+	attrs = append(attrs, fieldAttributes(err)...)
+	attrs = append(attrs, cfg.attrs...)
+
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(attrs...))
+
+	if code := cfg.classify(err); code != codes.Unset {
+		span.SetStatus(code, message)
+	}
+}
+
+// Type returns the exception.type of err: the Go type of its root cause, e.g. "*fs.PathError".
+// The root cause is the innermost error of the chain, also through layers not created by werr.
+func Type(err error) string {
+	for next := errors.Unwrap(err); next != nil; next = errors.Unwrap(err) {
+		err = next
+	}
+
+	return reflect.TypeOf(err).String()
+}
+
+// Message returns the exception.message of err: the messages of the chain and the root cause
+// on a single line, as rendered by werr.SingleLineFormatter.
+func Message(err error) string {
+	return werr.Formatter{Fn: werr.SingleLineFormatter()}.Format(err)
+}
+
+// Stacktrace returns the exception.stacktrace of err: the wrap sites of the chain
+// in the layout of a Go goroutine stack, innermost first, preceded by the error message.
+// The location of a wrap site is followed by its permalink when it is available, see werr.SetPermalinks.
+//
+//	open config.yaml: no such file or directory
+//
+//	main.load(...)
+//		/src/app/load.go:84
+//	main.main(...)
+//		/src/app/main.go:42
+func Stacktrace(err error) string {
+	frames := werr.Frames(err)
+
+	b := []byte(Message(err))
+	if len(frames) > 0 {
+		b = append(b, '\n')
+	}
+
+	for i := len(frames) - 1; i >= 0; i-- {
+		f := frames[i]
+
+		b = append(b, '\n')
+		b = append(b, f.FuncName...)
+		b = append(b, "(...)\n\t"...)
+		b = append(b, f.File...)
+		b = append(b, ':')
+		b = strconv.AppendInt(b, int64(f.Line), 10)
+
+		if link := f.Permalink(); link != "" {
+			b = append(b, ' ')
+			b = append(b, link...)
+		}
+	}
+
+	return string(b)
+}
+
+// fieldAttributes returns the fields of the chain as "werr.<key>" attributes.
+// A key recorded on several layers keeps the outermost value.
+func fieldAttributes(err error) []attribute.KeyValue {
+	var (
+		attrs []attribute.KeyValue
+		seen  map[string]bool
+	)
+
+	for _, f := range werr.Frames(err) {
+		for _, field := range f.Args {
+			if seen[field.Key] {
+				continue
+			}
+
+			if seen == nil {
+				seen = make(map[string]bool)
+			}
+
+			seen[field.Key] = true
+			attrs = append(attrs, attribute.String("werr."+field.Key, string(field.AppendValue(nil, werr.Redacted))))
+		}
+	}
+
+	return attrs
+}
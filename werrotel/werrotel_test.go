@@ -0,0 +1,169 @@
+package werrotel_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/safeblock-dev/werr"
+	"github.com/safeblock-dev/werr/werrotel"
+)
+
+var errNotFound = errors.New("not found")
+
+func load(id int) error {
+	return werr.WrapArgs(errNotFound, werr.Arg("id", id), werr.Arg("token", werr.Secret("t0k3n")))
+}
+
+func handle(id int) error {
+	return werr.Wrapf(load(id), "loading user")
+}
+
+// record runs fn within a span and returns the exported span.
+func record(t *testing.T, fn func(ctx context.Context)) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	ctx, span := provider.Tracer("werrotel").Start(context.Background(), "handle")
+	fn(ctx)
+	span.End()
+
+	spans := exporter.GetSpans().Snapshots()
+	require.Len(t, spans, 1)
+
+	return spans[0]
+}
+
+func attributes(event sdktrace.Event) map[attribute.Key]string {
+	attrs := make(map[attribute.Key]string, len(event.Attributes))
+	for _, kv := range event.Attributes {
+		attrs[kv.Key] = kv.Value.Emit()
+	}
+
+	return attrs
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		err := handle(42)
+
+		span := record(t, func(ctx context.Context) {
+			werrotel.Record(ctx, err, werrotel.WithAttributes(attribute.String("component", "users")))
+		})
+
+		require.Equal(t, codes.Error, span.Status().Code)
+		require.Equal(t, "loading user: id=42 token=‹×›: not found", span.Status().Description)
+
+		require.Len(t, span.Events(), 1)
+		require.Equal(t, "exception", span.Events()[0].Name)
+
+		attrs := attributes(span.Events()[0])
+		require.Equal(t, "*errors.errorString", attrs["exception.type"])
+		require.Equal(t, "loading user: id=42 token=‹×›: not found", attrs["exception.message"])
+		require.Equal(t, werr.Fingerprint(err), attrs[werrotel.FingerprintKey])
+		require.Equal(t, "42", attrs["werr.id"])
+		require.Equal(t, werr.RedactedMarker, attrs["werr.token"])
+		require.Equal(t, "users", attrs["component"])
+
+		stack := attrs["exception.stacktrace"]
+		require.True(t, strings.HasPrefix(stack, "loading user: id=42 token=‹×›: not found\n\n"), stack)
+		require.Less(t, strings.Index(stack, "werrotel_test.load(...)\n\t"), strings.Index(stack, "werrotel_test.handle(...)\n\t"))
+		require.Contains(t, stack, "/werrotel_test.go:")
+		require.NotContains(t, stack, "t0k3n")
+	})
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		span := record(t, func(ctx context.Context) {
+			werrotel.Record(ctx, nil)
+		})
+
+		require.Empty(t, span.Events())
+		require.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("when canceled", func(t *testing.T) {
+		t.Parallel()
+
+		span := record(t, func(ctx context.Context) {
+			werrotel.Record(ctx, werr.Wrap(context.Canceled))
+		})
+
+		require.Len(t, span.Events(), 1)
+		require.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("with classifier", func(t *testing.T) {
+		t.Parallel()
+
+		span := record(t, func(ctx context.Context) {
+			werrotel.Record(ctx, handle(1), werrotel.WithClassifier(func(err error) codes.Code {
+				if errors.Is(err, errNotFound) {
+					return codes.Unset
+				}
+
+				return codes.Error
+			}))
+		})
+
+		require.Len(t, span.Events(), 1)
+		require.Equal(t, codes.Unset, span.Status().Code)
+	})
+
+	t.Run("when not recording", func(t *testing.T) {
+		t.Parallel()
+
+		require.NotPanics(t, func() {
+			werrotel.RecordError(trace.SpanFromContext(context.Background()), handle(1))
+		})
+	})
+}
+
+func TestStacktrace(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when not wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, "not found", werrotel.Stacktrace(errNotFound))
+		require.Equal(t, "*errors.errorString", werrotel.Type(errNotFound))
+	})
+
+	t.Run("with foreign layers", func(t *testing.T) {
+		t.Parallel()
+
+		err := werr.Wrap(fmt.Errorf("handler: %w", handle(7)))
+
+		require.Equal(t, "*errors.errorString", werrotel.Type(err))
+		require.Equal(t, 3, strings.Count(werrotel.Stacktrace(err), "(...)\n\t"))
+	})
+}
+
+// nolint: paralleltest
+func TestStacktrace_Permalinks(t *testing.T) {
+	defer werr.SetPermalinks(werr.Permalinks{})
+
+	werr.SetPermalinks(werr.Permalinks{
+		Revision: "main",
+		Modules:  map[string]werr.Repository{"github.com/safeblock-dev/werr": {URL: "https://github.com/safeblock-dev/werr"}},
+	})
+
+	require.Contains(t, werrotel.Stacktrace(handle(1)),
+		"werrotel_test.go:24 https://github.com/safeblock-dev/werr/blob/main/werrotel/werrotel_test.go#L24\n")
+}
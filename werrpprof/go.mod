@@ -0,0 +1,19 @@
+module github.com/safeblock-dev/werr/werrpprof
+
+go 1.25.0
+
+require (
+	github.com/google/pprof v0.0.0-20260115054156-294ebfa9ad83
+	github.com/safeblock-dev/werr v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The replace directive builds the module against the werr sources of this repository and is ignored by
+// consumers: tag werr, then require that tag above before tagging the module (see Releasing in README.md).
+replace github.com/safeblock-dev/werr => ../
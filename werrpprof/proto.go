@@ -0,0 +1,233 @@
+package werrpprof
+
+import "time"
+
+// Field numbers of the messages of profile.proto.
+// See https://github.com/google/pprof/blob/main/proto/profile.proto.
+const (
+	profileSampleType        = 1
+	profileSample            = 2
+	profileLocation          = 4
+	profileFunction          = 5
+	profileStringTable       = 6
+	profileTimeNanos         = 9
+	profileDurationNanos     = 10
+	profilePeriodType        = 11
+	profilePeriod            = 12
+	profileDefaultSampleType = 14
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+	sampleLabel      = 3
+
+	labelKey = 1
+	labelStr = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+	lineLine       = 2
+
+	functionID         = 1
+	functionName       = 2
+	functionSystemName = 3
+	functionFilename   = 4
+)
+
+// encode returns the uncompressed profile.proto of the samples.
+func (p *Profiler) encode(now time.Time) []byte {
+	var (
+		b         buffer
+		strs      = map[string]int64{"": 0}
+		strTable  = []string{""}
+		locations = map[site]uint64{}
+		functions = map[[2]string]uint64{}
+	)
+
+	str := func(s string) int64 {
+		i, ok := strs[s]
+		if !ok {
+			i = int64(len(strTable))
+			strs[s] = i
+			strTable = append(strTable, s)
+		}
+
+		return i
+	}
+
+	valueType := func(typ, unit string) func(*buffer) {
+		t, u := str(typ), str(unit)
+
+		return func(b *buffer) {
+			b.int64(valueTypeType, t)
+			b.int64(valueTypeUnit, u)
+		}
+	}
+
+	sampleType := valueType("errors", "count")
+	b.message(profileSampleType, sampleType)
+
+	// Locations and functions are numbered from one in the order of their first occurrence.
+	var locs, funcs buffer
+
+	cause := str("cause")
+
+	for _, s := range p.order {
+		ids := make([]uint64, len(s.stack))
+
+		for i, site := range s.stack {
+			id, ok := locations[site]
+			if !ok {
+				fnKey := [2]string{site.funcName, site.file}
+
+				fnID, ok := functions[fnKey]
+				if !ok {
+					fnID = uint64(len(functions) + 1)
+					functions[fnKey] = fnID
+					name, file := str(site.funcName), str(site.file)
+
+					funcs.message(profileFunction, func(b *buffer) {
+						b.uint64(functionID, fnID)
+						b.int64(functionName, name)
+						b.int64(functionSystemName, name)
+						b.int64(functionFilename, file)
+					})
+				}
+
+				id = uint64(len(locations) + 1)
+				locations[site] = id
+				line := int64(site.line)
+
+				locs.message(profileLocation, func(b *buffer) {
+					b.uint64(locationID, id)
+					b.message(locationLine, func(b *buffer) {
+						b.uint64(lineFunctionID, fnID)
+						b.int64(lineLine, line)
+					})
+				})
+			}
+
+			ids[i] = id
+		}
+
+		value := s.count
+		causeStr := str(s.cause)
+
+		b.message(profileSample, func(b *buffer) {
+			b.packedUint64(sampleLocationID, ids)
+			b.packedInt64(sampleValue, []int64{value})
+			b.message(sampleLabel, func(b *buffer) {
+				b.int64(labelKey, cause)
+				b.int64(labelStr, causeStr)
+			})
+		})
+	}
+
+	b.raw(locs.data)
+	b.raw(funcs.data)
+
+	// All strings are interned at this point: the string table comes after the messages using it.
+	for _, s := range strTable {
+		b.string(profileStringTable, s)
+	}
+
+	b.int64(profileTimeNanos, p.start.UnixNano())
+	b.int64(profileDurationNanos, now.Sub(p.start).Nanoseconds())
+	b.message(profilePeriodType, sampleType)
+	b.int64(profilePeriod, 1)
+	b.int64(profileDefaultSampleType, strs["errors"])
+
+	return b.data
+}
+
+// buffer encodes protocol buffer fields.
+type buffer struct {
+	data []byte
+}
+
+// wire types.
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+func (b *buffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.data = append(b.data, byte(x)|0x80)
+		x >>= 7
+	}
+
+	b.data = append(b.data, byte(x))
+}
+
+func (b *buffer) key(field, wire int) {
+	b.varint(uint64(field)<<3 | uint64(wire))
+}
+
+// uint64 encodes a varint field; zero values are omitted, as in proto3.
+func (b *buffer) uint64(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+
+	b.key(field, wireVarint)
+	b.varint(x)
+}
+
+// int64 encodes a varint field; zero values are omitted, as in proto3.
+func (b *buffer) int64(field int, x int64) {
+	b.uint64(field, uint64(x))
+}
+
+// string encodes a length-delimited field. Empty strings are kept, so that
+// the first entry of the string table is present.
+func (b *buffer) string(field int, s string) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(s)))
+	b.data = append(b.data, s...)
+}
+
+func (b *buffer) packedUint64(field int, xs []uint64) {
+	if len(xs) == 0 {
+		return
+	}
+
+	var packed buffer
+	for _, x := range xs {
+		packed.varint(x)
+	}
+
+	b.bytes(field, packed.data)
+}
+
+func (b *buffer) packedInt64(field int, xs []int64) {
+	var packed buffer
+	for _, x := range xs {
+		packed.varint(uint64(x))
+	}
+
+	b.bytes(field, packed.data)
+}
+
+// message encodes an embedded message written by fn.
+func (b *buffer) message(field int, fn func(*buffer)) {
+	var msg buffer
+
+	fn(&msg)
+	b.bytes(field, msg.data)
+}
+
+func (b *buffer) bytes(field int, data []byte) {
+	b.key(field, wireBytes)
+	b.varint(uint64(len(data)))
+	b.data = append(b.data, data...)
+}
+
+// raw appends already encoded fields.
+func (b *buffer) raw(data []byte) {
+	b.data = append(b.data, data...)
+}
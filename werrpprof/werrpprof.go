@@ -0,0 +1,148 @@
+// Package werrpprof exports where errors come from as a pprof profile.
+//
+// A Profiler aggregates errors by the chain of their wrap sites and writes a
+// profile.proto where every sample is an error count and its stack is the chain,
+// innermost wrap site first like a call stack, so that the usual tools show error hot spots:
+//
+//	go tool pprof -http=:8080 errors.pb.gz
+//
+// Samples carry a "cause" label with the type of the root cause, e.g. for -tagfocus.
+// The profile is encoded without cgo or third-party packages.
+package werrpprof
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// site is a wrap site of a sample stack.
+type site struct {
+	funcName string
+	file     string
+	line     int
+}
+
+// sample is an aggregated error chain.
+type sample struct {
+	stack []site // stack is the chain, innermost wrap site first.
+	cause string
+	count int64
+}
+
+// Profiler aggregates errors by their chain of wrap sites. It is safe for concurrent use.
+type Profiler struct {
+	mu      sync.Mutex
+	start   time.Time
+	samples map[string]*sample
+	order   []*sample
+}
+
+// New returns an empty profiler.
+func New() *Profiler {
+	return &Profiler{
+		start:   time.Now(),
+		samples: make(map[string]*sample),
+	}
+}
+
+// Add counts an occurrence of err under its chain of wrap sites.
+// Errors without wrap sites are counted under an empty stack. Nil errors are ignored.
+func (p *Profiler) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	// The frames are outermost first, a stack is innermost first.
+	frames := werr.Frames(err)
+
+	stack := make([]site, len(frames))
+	for i, f := range frames {
+		stack[len(frames)-1-i] = site{funcName: f.FuncName, file: f.File, line: f.Line}
+	}
+
+	cause := err
+	for inner := errors.Unwrap(cause); inner != nil; inner = errors.Unwrap(cause) {
+		cause = inner
+	}
+
+	causeType := reflect.TypeOf(cause).String()
+
+	var key strings.Builder
+
+	key.WriteString(causeType)
+
+	for _, s := range stack {
+		key.WriteString("\x00" + s.funcName + "\x00" + s.file + "\x00" + strconv.Itoa(s.line))
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s, ok := p.samples[key.String()]
+	if !ok {
+		s = &sample{stack: stack, cause: causeType}
+		p.samples[key.String()] = s
+		p.order = append(p.order, s)
+	}
+
+	s.count++
+}
+
+// Hook counts the error; it can be registered with werr.OnWrap and werr.OnPanic.
+// With werr.OnWrap every wrap is counted under the chain built so far, so the
+// profile weighs wrap sites by the number of errors passing through them.
+func (p *Profiler) Hook(_ werr.Frame, err error) {
+	p.Add(err)
+}
+
+// Reset drops the aggregated samples and restarts the profiling period.
+func (p *Profiler) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.start = time.Now()
+	p.samples = make(map[string]*sample)
+	p.order = nil
+}
+
+// WriteTo writes the gzip-compressed profile of the errors added since the profiler
+// was created or reset.
+func (p *Profiler) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	data := p.encode(time.Now())
+	p.mu.Unlock()
+
+	cw := &countingWriter{w: w}
+	zw := gzip.NewWriter(cw)
+
+	if _, err := zw.Write(data); err != nil {
+		return cw.n, werr.Wrap(err)
+	}
+
+	if err := zw.Close(); err != nil {
+		return cw.n, werr.Wrap(err)
+	}
+
+	return cw.n, nil
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(b []byte) (int, error) {
+	n, err := c.w.Write(b)
+	c.n += int64(n)
+
+	return n, err //nolint: wrapcheck
+}
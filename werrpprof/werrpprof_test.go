@@ -0,0 +1,137 @@
+package werrpprof_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/pprof/profile"
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+	"github.com/safeblock-dev/werr/werrpprof"
+)
+
+var errNotFound = errors.New("not found")
+
+func load() error {
+	return werr.Wrap(errNotFound)
+}
+
+func handle() error {
+	return werr.Wrapf(load(), "loading user")
+}
+
+func cancel() error {
+	return werr.Wrap(context.Canceled)
+}
+
+// parse writes the profile and parses it back.
+func parse(t *testing.T, p *werrpprof.Profiler) *profile.Profile {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	n, err := p.WriteTo(&buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	prof, err := profile.Parse(&buf)
+	require.NoError(t, err)
+	require.NoError(t, prof.CheckValid())
+
+	return prof
+}
+
+// stack returns the function names of a sample, innermost first.
+func stack(s *profile.Sample) []string {
+	var names []string
+
+	for _, loc := range s.Location {
+		for _, line := range loc.Line {
+			names = append(names, line.Function.Name[strings.LastIndex(line.Function.Name, ".")+1:])
+		}
+	}
+
+	return names
+}
+
+func TestProfiler(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when empty", func(t *testing.T) {
+		t.Parallel()
+
+		prof := parse(t, werrpprof.New())
+		require.Empty(t, prof.Sample)
+		require.Equal(t, "errors", prof.SampleType[0].Type)
+		require.Equal(t, "count", prof.SampleType[0].Unit)
+	})
+
+	t.Run("aggregates by chain", func(t *testing.T) {
+		t.Parallel()
+
+		p := werrpprof.New()
+		for i := 0; i < 3; i++ {
+			p.Add(handle())
+		}
+
+		p.Add(load())
+		p.Add(cancel())
+		p.Add(errNotFound)
+		p.Add(nil)
+
+		prof := parse(t, p)
+		require.Len(t, prof.SampleType, 1)
+		require.Equal(t, "errors", prof.DefaultSampleType)
+		require.Equal(t, int64(1), prof.Period)
+		require.NotZero(t, prof.TimeNanos)
+		require.Len(t, prof.Sample, 4)
+
+		require.Equal(t, []string{"load", "handle"}, stack(prof.Sample[0]))
+		require.Equal(t, []int64{3}, prof.Sample[0].Value)
+		require.Equal(t, []string{"*errors.errorString"}, prof.Sample[0].Label["cause"])
+
+		require.Equal(t, []string{"load"}, stack(prof.Sample[1]))
+		require.Equal(t, []int64{1}, prof.Sample[1].Value)
+		require.Same(t, prof.Sample[0].Location[0], prof.Sample[1].Location[0])
+
+		require.Equal(t, []string{"cancel"}, stack(prof.Sample[2]))
+		require.Equal(t, []string{"*errors.errorString"}, prof.Sample[2].Label["cause"])
+
+		require.Empty(t, prof.Sample[3].Location)
+
+		line := prof.Sample[0].Location[0].Line[0]
+		require.Equal(t, "github.com/safeblock-dev/werr/werrpprof_test.load", line.Function.Name)
+		require.True(t, strings.HasSuffix(line.Function.Filename, "/werrpprof/werrpprof_test.go"), line.Function.Filename)
+		require.Equal(t, int64(20), line.Line)
+	})
+
+	t.Run("when reset", func(t *testing.T) {
+		t.Parallel()
+
+		p := werrpprof.New()
+		p.Add(handle())
+		p.Reset()
+		p.Add(load())
+
+		prof := parse(t, p)
+		require.Len(t, prof.Sample, 1)
+		require.Equal(t, []string{"load"}, stack(prof.Sample[0]))
+	})
+}
+
+// nolint: paralleltest
+func TestProfiler_Hook(t *testing.T) {
+	p := werrpprof.New()
+	defer werr.OnWrap(p.Hook)()
+
+	_ = handle()
+
+	prof := parse(t, p)
+	require.Len(t, prof.Sample, 2)
+	require.Equal(t, []string{"load"}, stack(prof.Sample[0]))
+	require.Equal(t, []string{"load", "handle"}, stack(prof.Sample[1]))
+}
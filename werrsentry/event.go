@@ -0,0 +1,193 @@
+// Package werrsentry builds Sentry events from werr errors and delivers them
+// without the Sentry SDK, so that the wrap sites of the chain become the frames
+// of the reported exceptions.
+//
+// Every layer of the chain is reported as an exception value, innermost first as
+// Sentry expects: the root cause, then one value per wrap site carrying its frame.
+// The fields recorded with werr.WrapArgs or werr.WrapCtx become tags, and the
+// fingerprint is werr.Fingerprint, so occurrences are grouped by wrap-site chain.
+package werrsentry
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"path"
+	"reflect"
+	"time"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// Event is a Sentry event payload.
+// See https://develop.sentry.dev/sdk/data-model/event-payloads/.
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Platform    string            `json:"platform"`
+	Level       string            `json:"level"`
+	Logger      string            `json:"logger,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+	Message     string            `json:"message,omitempty"`
+	Exception   ExceptionList     `json:"exception"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+}
+
+// ExceptionList is the exception interface of an event.
+type ExceptionList struct {
+	Values []Exception `json:"values"`
+}
+
+// Exception is a single error of the chain.
+type Exception struct {
+	Type       string      `json:"type"`
+	Value      string      `json:"value"`
+	Module     string      `json:"module,omitempty"`
+	Stacktrace *Stacktrace `json:"stacktrace,omitempty"`
+}
+
+// Stacktrace lists frames, oldest call first.
+type Stacktrace struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Frame is a wrap site.
+type Frame struct {
+	Function   string `json:"function"`
+	Module     string `json:"module"`
+	Filename   string `json:"filename"`
+	AbsPath    string `json:"abs_path"`
+	Lineno     int    `json:"lineno"`
+	InApp      bool   `json:"in_app"`
+	SourceLink string `json:"source_link,omitempty"` // SourceLink is the permalink of the wrap site, if enabled with werr.SetPermalinks.
+}
+
+// Builder builds Sentry events from errors.
+// The zero value builds events without release, environment or server name.
+type Builder struct {
+	Release     string            // Release is the version of the application, e.g. a commit hash.
+	Environment string            // Environment is the deployment environment, e.g. "production".
+	ServerName  string            // ServerName is the host the event comes from.
+	Tags        map[string]string // Tags are added to every event; fields of the chain take precedence.
+}
+
+// Build returns the event describing err.
+// Values marked with werr.Secret are redacted. If the error is nil, the function returns nil.
+func (b Builder) Build(err error) *Event {
+	if err == nil {
+		return nil
+	}
+
+	event := &Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC(),
+		Platform:    "go",
+		Level:       "error",
+		Release:     b.Release,
+		Environment: b.Environment,
+		ServerName:  b.ServerName,
+		Message:     werr.Formatter{Fn: werr.SingleLineFormatter()}.Format(err),
+		Fingerprint: []string{werr.Fingerprint(err)},
+	}
+
+	// The frames are outermost first, Sentry expects the innermost exception first.
+	var values []Exception
+
+	for _, f := range werr.Frames(err) {
+		values = append(values, layerException(f))
+
+		for _, arg := range f.Args {
+			event.tag(arg.Key, string(arg.AppendValue(nil, werr.Redacted)))
+		}
+	}
+
+	cause := err
+	for inner := errors.Unwrap(cause); inner != nil; inner = errors.Unwrap(cause) {
+		cause = inner
+	}
+
+	if _, ok := cause.(werr.Error); !ok { //nolint: errorlint
+		values = append(values, causeException(cause))
+	}
+
+	for k, v := range b.Tags {
+		event.tag(k, v)
+	}
+
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+
+	event.Exception.Values = values
+
+	return event
+}
+
+// tag sets a tag of the event unless it is already set.
+func (e *Event) tag(key, value string) {
+	if e.Tags == nil {
+		e.Tags = make(map[string]string)
+	}
+
+	if _, ok := e.Tags[key]; !ok {
+		e.Tags[key] = value
+	}
+}
+
+// causeException returns the exception of the root cause of the chain.
+func causeException(err error) Exception {
+	typ := reflect.TypeOf(err)
+
+	pkg := typ
+	if pkg.Kind() == reflect.Pointer {
+		pkg = pkg.Elem()
+	}
+
+	return Exception{
+		Type:   typ.String(),
+		Value:  err.Error(),
+		Module: pkg.PkgPath(),
+	}
+}
+
+// layerException returns the exception of a wrap site, with the site as its only frame.
+func layerException(frame werr.Frame) Exception {
+	f := newFrame(frame)
+
+	value := string(frame.AppendMessage(nil, werr.Redacted))
+	if value == "" {
+		value = f.Function + "()"
+	}
+
+	return Exception{
+		Type:       "werr.Error",
+		Value:      value,
+		Module:     f.Module,
+		Stacktrace: &Stacktrace{Frames: []Frame{f}},
+	}
+}
+
+// newFrame returns the Sentry frame of a wrap site.
+func newFrame(f werr.Frame) Frame {
+	return Frame{
+		Function:   f.Func(),
+		Module:     f.Package(),
+		Filename:   f.Package() + "/" + path.Base(f.File),
+		AbsPath:    f.File,
+		Lineno:     f.Line,
+		InApp:      true,
+		SourceLink: f.Permalink(),
+	}
+}
+
+// newEventID returns a random event ID: 32 hexadecimal characters without dashes.
+func newEventID() string {
+	var id [16]byte
+
+	_, _ = rand.Read(id[:])
+
+	return hex.EncodeToString(id[:])
+}
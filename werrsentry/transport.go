@@ -0,0 +1,158 @@
+package werrsentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// Transport delivers events, e.g. over HTTP or to a queue.
+type Transport interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+// TransportFunc is an adapter to use an ordinary function as a Transport.
+type TransportFunc func(ctx context.Context, event *Event) error
+
+// Send calls fn(ctx, event).
+func (fn TransportFunc) Send(ctx context.Context, event *Event) error {
+	return fn(ctx, event)
+}
+
+// Client builds events and sends them through its transport.
+type Client struct {
+	Builder             // Builder configures the events.
+	Transport Transport // Transport delivers the events.
+}
+
+// Capture builds the event describing err, sends it and returns its ID.
+// If the error is nil, the function sends nothing and returns an empty ID.
+func (c Client) Capture(ctx context.Context, err error) (string, error) {
+	event := c.Build(err)
+	if event == nil {
+		return "", nil
+	}
+
+	if err := c.Transport.Send(ctx, event); err != nil {
+		return "", werr.Wrap(err)
+	}
+
+	return event.EventID, nil
+}
+
+// sentryVersion is the version of the Sentry protocol used by HTTPTransport.
+const sentryVersion = "7"
+
+// HTTPTransport sends events as envelopes to the Sentry HTTP API.
+// See https://develop.sentry.dev/sdk/envelopes/.
+type HTTPTransport struct {
+	dsn      string
+	endpoint string
+	auth     string
+	client   *http.Client
+}
+
+// NewHTTPTransport returns a transport sending events to the project of a DSN,
+// e.g. "https://<key>@sentry.example.com/<project>", with the client (http.DefaultClient if nil).
+func NewHTTPTransport(dsn string, client *http.Client) (*HTTPTransport, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, werr.Wrapf(err, "parsing DSN")
+	}
+
+	project := strings.TrimPrefix(u.Path, "/")
+	if u.User == nil || u.User.Username() == "" || project == "" || u.Host == "" {
+		return nil, werr.Wrap(fmt.Errorf("invalid DSN %q: want <scheme>://<key>@<host>/<project>", u.Redacted()))
+	}
+
+	prefix := ""
+	if i := strings.LastIndex(project, "/"); i >= 0 {
+		prefix, project = "/"+project[:i], project[i+1:]
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPTransport{
+		dsn:      dsn,
+		endpoint: u.Scheme + "://" + u.Host + prefix + "/api/" + project + "/envelope/",
+		auth:     "Sentry sentry_version=" + sentryVersion + ", sentry_client=werrsentry/1.0, sentry_key=" + u.User.Username(),
+		client:   client,
+	}, nil
+}
+
+// Send posts the event as an envelope with a single event item.
+func (t *HTTPTransport) Send(ctx context.Context, event *Event) error {
+	body, err := Envelope(event, t.dsn)
+	if err != nil {
+		return werr.Wrap(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return werr.Wrap(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", t.auth)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return werr.Wrap(err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 { //nolint: mnd
+		return werr.Wrap(fmt.Errorf("sending event %s: unexpected status %s", event.EventID, resp.Status))
+	}
+
+	return nil
+}
+
+// Envelope returns the envelope carrying the event: a header line,
+// an item header line and the JSON payload of the event.
+func Envelope(event *Event, dsn string) ([]byte, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, werr.Wrap(err)
+	}
+
+	header, err := json.Marshal(struct {
+		EventID string    `json:"event_id"`
+		SentAt  time.Time `json:"sent_at"`
+		DSN     string    `json:"dsn,omitempty"`
+	}{event.EventID, time.Now().UTC(), dsn})
+	if err != nil {
+		return nil, werr.Wrap(err)
+	}
+
+	item, err := json.Marshal(struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}{"event", len(payload)})
+	if err != nil {
+		return nil, werr.Wrap(err)
+	}
+
+	var b bytes.Buffer
+
+	b.Write(header)
+	b.WriteByte('\n')
+	b.Write(item)
+	b.WriteByte('\n')
+	b.Write(payload)
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
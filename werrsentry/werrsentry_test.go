@@ -0,0 +1,235 @@
+package werrsentry_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+	"github.com/safeblock-dev/werr/werrsentry"
+)
+
+var errNotFound = errors.New("not found")
+
+func load(id int) error {
+	return werr.WrapArgs(errNotFound, werr.Arg("user_id", id), werr.Arg("token", werr.Secret("t0k3n")))
+}
+
+func handle(id int) error {
+	return werr.Wrapf(load(id), "loading user")
+}
+
+func TestBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, werrsentry.Builder{}.Build(nil))
+	})
+
+	t.Run("with error", func(t *testing.T) {
+		t.Parallel()
+
+		err := handle(42)
+		b := werrsentry.Builder{Release: "v1.2.3", Environment: "test", Tags: map[string]string{"team": "users", "user_id": "0"}}
+
+		event := b.Build(err)
+		require.Len(t, event.EventID, 32)
+		require.Equal(t, "go", event.Platform)
+		require.Equal(t, "error", event.Level)
+		require.Equal(t, "v1.2.3", event.Release)
+		require.Equal(t, "test", event.Environment)
+		require.Equal(t, []string{werr.Fingerprint(err)}, event.Fingerprint)
+		require.Equal(t, map[string]string{"team": "users", "user_id": "42", "token": werr.RedactedMarker}, event.Tags)
+
+		values := event.Exception.Values
+		require.Len(t, values, 3)
+
+		require.Equal(t, werrsentry.Exception{Type: "*errors.errorString", Value: "not found", Module: "errors"}, values[0])
+
+		require.Equal(t, "werr.Error", values[1].Type)
+		require.Equal(t, "user_id=42 token=‹×›", values[1].Value)
+		require.Equal(t, "github.com/safeblock-dev/werr/werrsentry_test", values[1].Module)
+		require.Len(t, values[1].Stacktrace.Frames, 1)
+
+		frame := values[1].Stacktrace.Frames[0]
+		require.Equal(t, "load", frame.Function)
+		require.Equal(t, "github.com/safeblock-dev/werr/werrsentry_test", frame.Module)
+		require.Equal(t, "github.com/safeblock-dev/werr/werrsentry_test/werrsentry_test.go", frame.Filename)
+		require.True(t, strings.HasSuffix(frame.AbsPath, "/werrsentry/werrsentry_test.go"))
+		require.Equal(t, 24, frame.Lineno)
+		require.True(t, frame.InApp)
+
+		require.Equal(t, "loading user", values[2].Value)
+		require.Equal(t, "handle", values[2].Stacktrace.Frames[0].Function)
+	})
+
+	t.Run("without message", func(t *testing.T) {
+		t.Parallel()
+
+		event := werrsentry.Builder{}.Build(werr.Wrap(errNotFound))
+		require.Len(t, event.Exception.Values, 2)
+		require.Equal(t, "TestBuilder_Build.func3()", event.Exception.Values[1].Value)
+		require.Nil(t, event.Tags)
+	})
+}
+
+// envelope is a parsed envelope.
+type envelope struct {
+	Header struct {
+		EventID string `json:"event_id"`
+		SentAt  string `json:"sent_at"`
+		DSN     string `json:"dsn"`
+	}
+	Item struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	Event werrsentry.Event
+}
+
+// parseEnvelope parses an envelope with a single item, validating the length of its payload.
+func parseEnvelope(t *testing.T, r io.Reader) envelope {
+	t.Helper()
+
+	var env envelope
+
+	br := bufio.NewReader(r)
+
+	line, err := br.ReadBytes('\n')
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(line, &env.Header))
+
+	line, err = br.ReadBytes('\n')
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(line, &env.Item))
+
+	payload := make([]byte, env.Item.Length)
+	_, err = io.ReadFull(br, payload)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(payload, &env.Event))
+
+	rest, err := io.ReadAll(br)
+	require.NoError(t, err)
+	require.Equal(t, "\n", string(rest))
+
+	return env
+}
+
+// nolint: paralleltest
+func TestBuilder_Build_SourceLink(t *testing.T) {
+	defer werr.SetPermalinks(werr.Permalinks{})
+
+	werr.SetPermalinks(werr.Permalinks{
+		Revision: "main",
+		Modules:  map[string]werr.Repository{"github.com/safeblock-dev/werr": {URL: "https://github.com/safeblock-dev/werr"}},
+	})
+
+	frame := werrsentry.Builder{}.Build(handle(1)).Exception.Values[1].Stacktrace.Frames[0]
+	require.Equal(t, "https://github.com/safeblock-dev/werr/blob/main/werrsentry/werrsentry_test.go#L24", frame.SourceLink)
+}
+
+func TestHTTPTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends envelope", func(t *testing.T) {
+		t.Parallel()
+
+		type request struct {
+			method, path, contentType, auth string
+			body                            []byte
+		}
+
+		requests := make(chan request, 1)
+
+		server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			requests <- request{r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("X-Sentry-Auth"), body}
+		}))
+		defer server.Close()
+
+		dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/sentry/17"
+
+		transport, err := werrsentry.NewHTTPTransport(dsn, server.Client())
+		require.NoError(t, err)
+
+		client := werrsentry.Client{Builder: werrsentry.Builder{Release: "v1"}, Transport: transport}
+
+		id, err := client.Capture(context.Background(), handle(7))
+		require.NoError(t, err)
+
+		req := <-requests
+		require.Equal(t, http.MethodPost, req.method)
+		require.Equal(t, "/sentry/api/17/envelope/", req.path)
+		require.Equal(t, "application/x-sentry-envelope", req.contentType)
+		require.Equal(t, "Sentry sentry_version=7, sentry_client=werrsentry/1.0, sentry_key=public", req.auth)
+
+		env := parseEnvelope(t, bytes.NewReader(req.body))
+		require.Equal(t, id, env.Header.EventID)
+		require.Equal(t, dsn, env.Header.DSN)
+		require.NotEmpty(t, env.Header.SentAt)
+		require.Equal(t, "event", env.Item.Type)
+		require.Equal(t, id, env.Event.EventID)
+		require.Equal(t, "v1", env.Event.Release)
+		require.Equal(t, "7", env.Event.Tags["user_id"])
+		require.Len(t, env.Event.Exception.Values, 3)
+		require.Equal(t, "handle", env.Event.Exception.Values[2].Stacktrace.Frames[0].Function)
+	})
+
+	t.Run("when server fails", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		transport, err := werrsentry.NewHTTPTransport(strings.Replace(server.URL, "://", "://key@", 1)+"/1", server.Client())
+		require.NoError(t, err)
+
+		id, err := werrsentry.Client{Transport: transport}.Capture(context.Background(), handle(1))
+		require.ErrorContains(t, err, "unexpected status 429 Too Many Requests")
+		require.Empty(t, id)
+	})
+
+	t.Run("when invalid DSN", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := werrsentry.NewHTTPTransport("https://sentry.example.com/1", nil)
+		require.ErrorContains(t, err, "invalid DSN")
+
+		_, err = werrsentry.NewHTTPTransport("https://key@sentry.example.com", nil)
+		require.ErrorContains(t, err, "invalid DSN")
+	})
+}
+
+func TestTransportFunc(t *testing.T) {
+	t.Parallel()
+
+	var sent []*werrsentry.Event
+
+	client := werrsentry.Client{Transport: werrsentry.TransportFunc(func(_ context.Context, event *werrsentry.Event) error {
+		sent = append(sent, event)
+
+		return nil
+	})}
+
+	id, err := client.Capture(context.Background(), nil)
+	require.NoError(t, err)
+	require.Empty(t, id)
+
+	id, err = client.Capture(context.Background(), handle(3))
+	require.NoError(t, err)
+	require.Len(t, sent, 1)
+	require.Equal(t, sent[0].EventID, id)
+}
@@ -0,0 +1,127 @@
+// Package werrtest provides test assertions for errors wrapped with werr.
+//
+// The assertions check where an error was wrapped by function name rather than by
+// comparing Error() output, which includes line numbers and changes with every edit.
+// They work with a plain testing.T and report failures with Errorf.
+//
+// Functions are named as in Go source, qualified by the package name: "user.Load",
+// "user.(*Store).Get" or "user.Load.func1" for a closure. A fully qualified name
+// such as "example.com/app/user.Load" is accepted as well.
+package werrtest
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/safeblock-dev/werr"
+)
+
+// AssertWrappedAt checks that the outermost werr layer of err was wrapped in the function fn.
+func AssertWrappedAt(t testing.TB, err error, fn string) bool {
+	t.Helper()
+
+	funcs := Funcs(err)
+	if len(funcs) == 0 {
+		t.Errorf("error %q was not wrapped with werr, want wrapped at %s", errorText(err), fn)
+
+		return false
+	}
+
+	if !matchFunc(funcs[0], fn) {
+		t.Errorf("error was wrapped at %s, want %s\nchain: %s", funcs[0], fn, strings.Join(funcs, " -> "))
+
+		return false
+	}
+
+	return true
+}
+
+// AssertChain checks that the werr layers of err were wrapped in the functions fns, outermost first.
+// Layers added by other packages, such as fmt.Errorf, are skipped.
+func AssertChain(t testing.TB, err error, fns ...string) bool {
+	t.Helper()
+
+	funcs := Funcs(err)
+
+	ok := len(funcs) == len(fns)
+	for i := 0; ok && i < len(fns); i++ {
+		ok = matchFunc(funcs[i], fns[i])
+	}
+
+	if !ok {
+		t.Errorf("error was wrapped at:\n\t%s\nwant:\n\t%s", strings.Join(funcs, "\n\t"), strings.Join(fns, "\n\t"))
+	}
+
+	return ok
+}
+
+// AssertCause checks that the root cause of err, as returned by werr.Cause, is target
+// or wraps target according to errors.Is.
+func AssertCause(t testing.TB, err, target error) bool {
+	t.Helper()
+
+	cause := werr.Cause(err)
+	if cause == nil || !errors.Is(cause, target) {
+		t.Errorf("error cause is %q (%T), want %q (%T)", errorText(cause), cause, errorText(target), target)
+
+		return false
+	}
+
+	return true
+}
+
+// Funcs returns the fully qualified names of the functions where the werr layers
+// of err were wrapped, outermost first.
+func Funcs(err error) []string {
+	var funcs []string
+
+	for _, f := range werr.Frames(err) {
+		funcs = append(funcs, f.FuncName)
+	}
+
+	return funcs
+}
+
+// matchFunc reports whether the fully qualified function name matches the wanted name,
+// either fully qualified or qualified by the package name.
+func matchFunc(name, want string) bool {
+	return name == want || strings.HasSuffix(name, "/"+want)
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+
+	return err.Error()
+}
+
+var (
+	lineRe      = regexp.MustCompile(`(\.go):\d+`)                                                     //nolint: gochecknoglobals
+	pathRe      = regexp.MustCompile(`(^|[\s(])(?:[A-Za-z]:)?[/\\](?:[^\s/\\]+[/\\])*([^\s/\\]+\.go)`) //nolint: gochecknoglobals
+	offsetRe    = regexp.MustCompile(` \+0x[0-9a-f]+`)                                                 //nolint: gochecknoglobals
+	goroutineRe = regexp.MustCompile(`goroutine \d+`)                                                  //nolint: gochecknoglobals
+	argsRe      = regexp.MustCompile(`\((?:[{}, .]*0x[0-9a-f]+\??[{}, .]*)+\)`)                        //nolint: gochecknoglobals
+)
+
+// Normalize strips the parts of a formatted error that change between builds and
+// machines, for comparison with golden files: line numbers, absolute file paths
+// (only the base name is kept) and, in goroutine stacks recorded by werr.PanicToError,
+// goroutine numbers, program counter offsets and argument values.
+//
+//	github.com/acme/app/user.go:21	Load()	load user
+//
+// becomes
+//
+//	github.com/acme/app/user.go	Load()	load user
+func Normalize(s string) string {
+	s = lineRe.ReplaceAllString(s, "$1")
+	s = pathRe.ReplaceAllString(s, "$1$2")
+	s = offsetRe.ReplaceAllString(s, "")
+	s = goroutineRe.ReplaceAllString(s, "goroutine N")
+	s = argsRe.ReplaceAllString(s, "(...)")
+
+	return s
+}
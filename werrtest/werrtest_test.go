@@ -0,0 +1,180 @@
+package werrtest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/safeblock-dev/werr"
+	"github.com/safeblock-dev/werr/werrtest"
+)
+
+// recorder records the failures reported by an assertion.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func load() error {
+	return werr.Wrapf(io.EOF, "load")
+}
+
+func fetch() error {
+	return werr.Wrap(fmt.Errorf("fetch: %w", load()))
+}
+
+type store struct{}
+
+func (*store) get() error {
+	return werr.Wrap(fetch())
+}
+
+func TestAssertWrappedAt(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with matching function", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.True(t, werrtest.AssertWrappedAt(r, fetch(), "werrtest_test.fetch"))
+		require.True(t, werrtest.AssertWrappedAt(r, fetch(), "github.com/safeblock-dev/werr/werrtest_test.fetch"))
+		require.True(t, werrtest.AssertWrappedAt(r, new(store).get(), "werrtest_test.(*store).get"))
+		require.Empty(t, r.failures)
+	})
+
+	t.Run("with other function", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.False(t, werrtest.AssertWrappedAt(r, fetch(), "werrtest_test.load"))
+		require.False(t, werrtest.AssertWrappedAt(r, fetch(), "test.fetch"))
+		require.Len(t, r.failures, 2)
+		require.Contains(t, r.failures[0], "error was wrapped at github.com/safeblock-dev/werr/werrtest_test.fetch, want werrtest_test.load")
+	})
+
+	t.Run("when not wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.False(t, werrtest.AssertWrappedAt(r, io.EOF, "werrtest_test.load"))
+		require.False(t, werrtest.AssertWrappedAt(r, nil, "werrtest_test.load"))
+		require.Equal(t, []string{
+			`error "EOF" was not wrapped with werr, want wrapped at werrtest_test.load`,
+			`error "<nil>" was not wrapped with werr, want wrapped at werrtest_test.load`,
+		}, r.failures)
+	})
+}
+
+func TestAssertChain(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with matching chain", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.True(t, werrtest.AssertChain(r, new(store).get(),
+			"werrtest_test.(*store).get", "werrtest_test.fetch", "werrtest_test.load"))
+		require.True(t, werrtest.AssertChain(r, io.EOF))
+		require.Empty(t, r.failures)
+	})
+
+	t.Run("with other chain", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.False(t, werrtest.AssertChain(r, fetch(), "werrtest_test.fetch"))
+		require.False(t, werrtest.AssertChain(r, fetch(), "werrtest_test.load", "werrtest_test.fetch"))
+		require.Len(t, r.failures, 2)
+		require.Equal(t, "error was wrapped at:\n"+
+			"\tgithub.com/safeblock-dev/werr/werrtest_test.fetch\n"+
+			"\tgithub.com/safeblock-dev/werr/werrtest_test.load\n"+
+			"want:\n"+
+			"\twerrtest_test.fetch", r.failures[0])
+	})
+}
+
+func TestAssertCause(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with matching cause", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.True(t, werrtest.AssertCause(r, new(store).get(), io.EOF))
+		require.True(t, werrtest.AssertCause(r, io.EOF, io.EOF))
+		require.Empty(t, r.failures)
+	})
+
+	t.Run("with other cause", func(t *testing.T) {
+		t.Parallel()
+
+		r := &recorder{TB: t}
+		require.False(t, werrtest.AssertCause(r, load(), io.ErrUnexpectedEOF))
+		require.False(t, werrtest.AssertCause(r, nil, io.EOF))
+		require.Equal(t, []string{
+			`error cause is "EOF" (*errors.errorString), want "unexpected EOF" (*errors.errorString)`,
+			`error cause is "<nil>" (<nil>), want "EOF" (*errors.errorString)`,
+		}, r.failures)
+	})
+}
+
+func TestFuncs(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, []string{
+		"github.com/safeblock-dev/werr/werrtest_test.(*store).get",
+		"github.com/safeblock-dev/werr/werrtest_test.fetch",
+		"github.com/safeblock-dev/werr/werrtest_test.load",
+	}, werrtest.Funcs(new(store).get()))
+	require.Empty(t, werrtest.Funcs(errors.New("plain")))
+	require.Empty(t, werrtest.Funcs(nil))
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	t.Run("with werr trace", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t,
+			"github.com/acme/app/user.go\tLoad()\tload user\nconnection refused",
+			werrtest.Normalize("github.com/acme/app/user.go:21\tLoad()\tload user\nconnection refused"))
+	})
+
+	t.Run("with panic stack", func(t *testing.T) {
+		t.Parallel()
+
+		in := "example.com/app.Run.func1/worker.go:17\t1()\tpanic recovered\n" +
+			"goroutine 7 [running]:\n" +
+			"example.com/app.(*Worker).handle(0xc000010000, {0x1, 0x2?})\n" +
+			"\t/src/app/worker.go:30 +0x3e\n" +
+			"main.main()\n" +
+			"\tC:\\src\\app\\main.go:9 +0x1d\n"
+		exp := "example.com/app.Run.func1/worker.go\t1()\tpanic recovered\n" +
+			"goroutine N [running]:\n" +
+			"example.com/app.(*Worker).handle(...)\n" +
+			"\tworker.go\n" +
+			"main.main()\n" +
+			"\tmain.go\n"
+
+		require.Equal(t, exp, werrtest.Normalize(in))
+	})
+
+	t.Run("with real error", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t,
+			"github.com/safeblock-dev/werr/werrtest_test/werrtest_test.go\tfetch()\n"+
+				"fetch: github.com/safeblock-dev/werr/werrtest_test/werrtest_test.go\tload()\tload\nEOF",
+			werrtest.Normalize(fetch().Error()))
+	})
+}
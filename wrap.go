@@ -1,8 +1,6 @@
 package werr
 
-import (
-	"fmt"
-)
+import "fmt"
 
 // Wrap takes an error and returns a new wrapped error.
 // If the input error (err) is nil, the function returns nil.
@@ -13,19 +11,64 @@ func Wrap(err error) error {
 		return nil
 	}
 
-	return newError(err, "")
+	e := newError(err, "")
+	_wrapHooks.call(e)
+
+	return e
 }
 
 // Wrapf takes an error, a format string, and optional arguments, and returns a new wrapped error.
 // If the input error (err) is nil, the function returns nil.
 // Otherwise, it creates a new wrapped error using the input error
 // and formats the message text based on the provided format and arguments.
+// Arguments marked with Secret, or of a type with a registered redactor, are redacted
+// in the default output; see Redaction.
 func Wrapf(err error, format string, a ...any) error {
 	if err == nil {
 		return nil
 	}
 
-	return newError(err, fmt.Sprintf(format, a...))
+	var msg, rawMsg string
+
+	// Arguments that need no redaction are formatted directly, which also keeps Wrapf
+	// a printf wrapper for go vet: format and a must be passed to fmt.Sprintf unchanged.
+	if redacted, ok := redactArgs(a, Redacted); ok {
+		msg, rawMsg = sprintfRedacted(format, a, redacted)
+	} else {
+		msg = fmt.Sprintf(format, a...)
+	}
+
+	e := newError(err, msg)
+	e.rawMsg = rawMsg
+
+	if format != msg {
+		e.format = format
+	}
+
+	_wrapHooks.call(e)
+
+	return e
+}
+
+// WrapArgs takes an error and named function arguments, and returns a new wrapped error.
+// If the input error (err) is nil, the function returns nil.
+// The arguments are rendered as "name=value" pairs after the message text
+// and are available as structured data through Error.Args.
+// Example: werr.WrapArgs(err, werr.Arg("userID", userID), werr.Arg("limit", limit)).
+func WrapArgs(err error, args ...Field) error {
+	if err == nil {
+		return nil
+	}
+
+	e := newError(err, "")
+	if len(args) > 0 {
+		fields := append([]Field(nil), args...)
+		e.args = &fields
+	}
+
+	_wrapHooks.call(e)
+
+	return e
 }
 
 // Wrapt takes a value, an error and returns a new wrapped error.
@@ -37,7 +80,10 @@ func Wrapt[T any](val T, err error) (T, error) { //nolint: ireturn
 		return val, nil
 	}
 
-	return val, newError(err, "")
+	e := newError(err, "")
+	_wrapHooks.call(e)
+
+	return val, e
 }
 
 // Unwrap retrieves the underlying error wrapped by the provided error.
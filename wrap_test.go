@@ -248,3 +248,35 @@ func TestIsWrap(t *testing.T) {
 		require.False(t, werr.IsWrap(nil))
 	})
 }
+
+func TestWrapArgs(t *testing.T) {
+	t.Parallel()
+
+	originalErr := errors.New("original error")
+
+	t.Run("with args", func(t *testing.T) {
+		t.Parallel()
+
+		wrappedErr := werr.WrapArgs(originalErr, werr.Arg("userID", 42), werr.Arg("limit", 10))
+
+		require.IsType(t, werr.Error{}, wrappedErr)
+		require.ErrorIs(t, wrappedErr, originalErr)
+		require.Equal(t, []werr.Field{{Key: "userID", Value: 42}, {Key: "limit", Value: 10}}, wrappedErr.(werr.Error).Args())
+		require.Contains(t, wrappedErr.Error(), "\tuserID=42 limit=10\noriginal error")
+	})
+
+	t.Run("without args", func(t *testing.T) {
+		t.Parallel()
+
+		wrappedErr := werr.WrapArgs(originalErr)
+
+		require.Nil(t, wrappedErr.(werr.Error).Args())
+		require.Contains(t, wrappedErr.Error(), "func2()\noriginal error")
+	})
+
+	t.Run("when nil", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, werr.WrapArgs(nil, werr.Arg("userID", 42)))
+	})
+}